@@ -0,0 +1,12 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+var codegenCmd = &cobra.Command{
+	Use:   "codegen",
+	Short: "Generate typed code from a config schema",
+}
+
+func init() {
+	rootCmd.AddCommand(codegenCmd)
+}
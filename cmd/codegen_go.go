@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"github.com/besrabasant/valuesctl/internal/fileutil"
+	"github.com/besrabasant/valuesctl/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+var (
+	codegenSchemaPath string
+	codegenOut        string
+	codegenTypeName   string
+	codegenGoPackage  string
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "go",
+		Short: "Generate Go structs (json/yaml tags, enum constants, Default methods) from a YAML JSON-Schema",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out, err := schema.GenerateGo(codegenSchemaPath, codegenGoPackage, codegenTypeName)
+			if err != nil {
+				return err
+			}
+			return writeOutput(cmd, codegenOut, out)
+		},
+	}
+
+	cmd.Flags().StringVarP(&codegenSchemaPath, "schema", "s", "config.schema.yaml", "path to YAML JSON-Schema")
+	cmd.Flags().StringVarP(&codegenOut, "out", "o", "", "output path (default: stdout)")
+	cmd.Flags().StringVar(&codegenTypeName, "type-name", "Config", "name of the root type")
+	cmd.Flags().StringVar(&codegenGoPackage, "package", "config", "Go package name")
+
+	codegenCmd.AddCommand(cmd)
+}
+
+// writeOutput writes generated output to path, or to stdout when path is empty.
+func writeOutput(cmd *cobra.Command, path string, out []byte) error {
+	if path == "" {
+		_, err := cmd.OutOrStdout().Write(out)
+		return err
+	}
+	return fileutil.WriteFileAtomic(path, out)
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"github.com/besrabasant/valuesctl/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "ts",
+		Short: "Generate TypeScript interfaces (JSDoc, enum unions, optional fields) from a YAML JSON-Schema",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out, err := schema.GenerateTS(codegenSchemaPath, codegenTypeName)
+			if err != nil {
+				return err
+			}
+			return writeOutput(cmd, codegenOut, out)
+		},
+	}
+
+	cmd.Flags().StringVarP(&codegenSchemaPath, "schema", "s", "config.schema.yaml", "path to YAML JSON-Schema")
+	cmd.Flags().StringVarP(&codegenOut, "out", "o", "", "output path (default: stdout)")
+	cmd.Flags().StringVar(&codegenTypeName, "type-name", "Config", "name of the root interface")
+
+	codegenCmd.AddCommand(cmd)
+}
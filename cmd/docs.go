@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"github.com/besrabasant/valuesctl/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+var (
+	docsSchemaPath string
+	docsFormat     string
+	docsOut        string
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "docs",
+		Short: "Render a Markdown/HTML configuration reference from a YAML JSON-Schema",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out, err := schema.RenderDocs(docsSchemaPath, docsFormat)
+			if err != nil {
+				return err
+			}
+			return writeOutput(cmd, docsOut, out)
+		},
+	}
+
+	cmd.Flags().StringVarP(&docsSchemaPath, "schema", "s", "config.schema.yaml", "path to YAML JSON-Schema")
+	cmd.Flags().StringVar(&docsFormat, "format", "markdown", "output format: markdown|html")
+	cmd.Flags().StringVarP(&docsOut, "out", "o", "", "output path (default: stdout)")
+
+	rootCmd.AddCommand(cmd)
+}
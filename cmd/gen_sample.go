@@ -1,28 +1,101 @@
 package cmd
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/besrabasant/valuesctl/internal/fileutil"
 	"github.com/besrabasant/valuesctl/internal/schema"
 	"github.com/spf13/cobra"
 )
 
-var sampleOut string
+var (
+	sampleSchemaPath string
+	sampleOut        string
+	sampleArrayItems string
+	sampleMode       string
+	sampleComments   string
+	sampleMergeInto  string
+	sampleFlagRemove bool
+	sampleFormat     string
+	sampleEnvSep     string
+)
 
 func init() {
 	cmd := &cobra.Command{
 		Use:   "gen-sample",
 		Short: "Generate a sample config from a YAML JSON-Schema (types & descriptions respected)",
+		// failed random-config tests are reported, not usage errors
+		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			y, err := schema.BuildSampleFromSchema(schemaPath)
+			opts := schema.SampleOptions{
+				ArrayItems:   sampleArrayItems,
+				Mode:         sampleMode,
+				CommentStyle: sampleComments,
+				Format:       sampleFormat,
+				EnvSeparator: sampleEnvSep,
+			}
+			if sampleRandom {
+				return randomSamples(cmd, opts)
+			}
+			if sampleMergeInto != "" {
+				if sampleFormat != "yaml" {
+					return fmt.Errorf("--merge-into only supports YAML configs")
+				}
+				return mergeSample(cmd, opts)
+			}
+			y, err := schema.BuildSampleFromSchema(sampleSchemaPath, opts)
 			if err != nil {
 				return err
 			}
-			return fileutil.WriteFileAtomic(sampleOut, y)
+			return fileutil.WriteFileAtomic(sampleOutPath(cmd), y)
 		},
 	}
 
-	cmd.Flags().StringVarP(&schemaPath, "schema", "s", "config.schema.yaml", "path to YAML JSON-Schema")
+	cmd.Flags().StringVarP(&sampleSchemaPath, "schema", "s", "config.schema.yaml", "path to YAML JSON-Schema")
 	cmd.Flags().StringVarP(&sampleOut, "out", "o", "config.sample.yaml", "output sample config path")
+	cmd.Flags().StringVar(&sampleArrayItems, "array-items", "0", "example elements per array: 0, 1 or minItems")
+	cmd.Flags().StringVar(&sampleComments, "comment-style", "description", "key comments: description, full (title, type, enum, bounds, pattern, format, default, required) or none")
+	cmd.Flags().StringVar(&sampleFormat, "format", "yaml", "output format: yaml, json, toml or env")
+	cmd.Flags().StringVar(&sampleEnvSep, "env-separator", "__", "with --format env: separator between nested key names")
+	cmd.Flags().StringVar(&sampleMergeInto, "merge-into", "", "add the schema keys missing from this existing config to it (in place unless --out is given), keeping its values, comments and order")
+	cmd.Flags().BoolVar(&sampleFlagRemove, "flag-removed", false, `with --merge-into: mark config keys the schema no longer declares with a "# not in schema" comment`)
+	cmd.Flags().BoolVar(&sampleRandom, "random", false, "generate random schema-valid configs instead of a placeholder sample")
+	cmd.Flags().Uint64Var(&sampleSeed, "seed", 0, "with --random: random seed (default: time-based, printed)")
+	cmd.Flags().IntVar(&sampleCount, "count", 1, "with --random: number of configs (written as <out>-1, <out>-2, … when more than one)")
+	cmd.Flags().StringVarP(&sampleTestTpl, "template", "t", "", "with --random: instead of writing the configs, render and patch each with this template and report failures")
+	cmd.Flags().StringVarP(&sampleTestValues, "file", "f", "", "with --template: values.yaml to patch (default: empty)")
+	cmd.Flags().StringVar(&sampleFailuresDir, "failures-dir", "random-failures", "with --template: directory for configs that failed")
+	cmd.Flags().StringVar(&sampleMode, "mode", "full", "full (all keys), minimal (required keys only) or annotated (required keys, optional ones commented out)")
 
 	rootCmd.AddCommand(cmd)
 }
+
+// mergeSample implements gen-sample --merge-into.
+func mergeSample(cmd *cobra.Command, opts schema.SampleOptions) error {
+	res, err := schema.MergeSampleInto(sampleSchemaPath, sampleMergeInto, opts, sampleFlagRemove)
+	if err != nil {
+		return err
+	}
+	w := cmd.ErrOrStderr()
+	if len(res.Added) > 0 {
+		fmt.Fprintf(w, "added: %s\n", strings.Join(res.Added, ", "))
+	}
+	if len(res.Removed) > 0 {
+		fmt.Fprintf(w, "not in schema: %s\n", strings.Join(res.Removed, ", "))
+	}
+	target := sampleMergeInto
+	if cmd.Flags().Changed("out") {
+		target = sampleOut
+	}
+	return fileutil.WriteFileAtomic(target, res.Output)
+}
+
+// sampleOutPath is --out, or config.sample.<format> when --out is not set and
+// the format is not YAML.
+func sampleOutPath(cmd *cobra.Command) string {
+	if !cmd.Flags().Changed("out") && sampleFormat != "yaml" {
+		return "config.sample." + sampleFormat
+	}
+	return sampleOut
+}
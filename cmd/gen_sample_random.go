@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/besrabasant/valuesctl/internal/fileutil"
+	"github.com/besrabasant/valuesctl/internal/patcher"
+	"github.com/besrabasant/valuesctl/internal/schema"
+	"github.com/besrabasant/valuesctl/internal/tmpl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sampleRandom      bool
+	sampleSeed        uint64
+	sampleCount       int
+	sampleTestTpl     string
+	sampleTestValues  string
+	sampleFailuresDir string
+)
+
+// randomSamples implements gen-sample --random: it writes count random
+// configs, or with --template property-tests the template against them.
+func randomSamples(cmd *cobra.Command, opts schema.SampleOptions) error {
+	if sampleCount < 1 {
+		return fmt.Errorf("--count must be at least 1")
+	}
+	seed := sampleSeed
+	if !cmd.Flags().Changed("seed") {
+		seed = uint64(time.Now().UnixNano())
+		fmt.Fprintf(cmd.ErrOrStderr(), "seed: %d\n", seed)
+	}
+	cfgs, err := schema.RandomConfigs(sampleSchemaPath, seed, sampleCount)
+	if err != nil {
+		return err
+	}
+
+	if sampleTestTpl != "" {
+		return testRandomConfigs(cmd, cfgs, seed)
+	}
+
+	out := sampleOutPath(cmd)
+	for i, cfg := range cfgs {
+		b, err := schema.FormatConfig(cfg, opts)
+		if err != nil {
+			return err
+		}
+		target := out
+		if len(cfgs) > 1 {
+			ext := filepath.Ext(out)
+			target = fmt.Sprintf("%s-%d%s", strings.TrimSuffix(out, ext), i+1, ext)
+		}
+		if err := fileutil.WriteFileAtomic(target, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// testRandomConfigs runs each config through the same steps as patch
+// (validation, defaults, render, merge patch) and saves the ones that fail.
+func testRandomConfigs(cmd *cobra.Command, cfgs []map[string]any, seed uint64) error {
+	oldYAML := []byte("{}")
+	if sampleTestValues != "" {
+		var err error
+		if oldYAML, err = fileutil.ReadFile(sampleTestValues); err != nil {
+			return err
+		}
+	}
+	tmp, err := os.MkdirTemp("", "valuesctl-random-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	failed := 0
+	for i, cfg := range cfgs {
+		b, err := schema.FormatConfig(cfg, schema.SampleOptions{})
+		if err != nil {
+			return err
+		}
+		cfgFile := filepath.Join(tmp, fmt.Sprintf("config-%d.yaml", i+1))
+		if err := os.WriteFile(cfgFile, b, 0o644); err != nil {
+			return err
+		}
+		if stage, err := patchOnce(cfgFile, oldYAML); err != nil {
+			failed++
+			saved := filepath.Join(sampleFailuresDir, fmt.Sprintf("config-%d-%d.yaml", seed, i+1))
+			if err := os.MkdirAll(sampleFailuresDir, 0o755); err != nil {
+				return err
+			}
+			if err := fileutil.WriteFileAtomic(saved, b); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "config %d: %s: %v\n  saved to %s\n", i+1, stage, err, saved)
+		}
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "%d random configs, %d failed (seed %d)\n", len(cfgs), failed, seed)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d random configs failed", failed, len(cfgs))
+	}
+	return nil
+}
+
+// patchOnce validates cfgFile, renders the template and patches oldYAML,
+// returning the failing stage.
+func patchOnce(cfgFile string, oldYAML []byte) (string, error) {
+	data, _, err := schema.PrepareConfig(sampleSchemaPath, cfgFile, schema.ConfigOptions{Validate: true, ApplyDefaults: true})
+	if err != nil {
+		return "validate", err
+	}
+	desired, err := tmpl.RenderWithData(sampleTestTpl, data)
+	if err != nil {
+		return "render", err
+	}
+	if _, err := patcher.MergePatchYAML(oldYAML, desired); err != nil {
+		return "patch", err
+	}
+	return "", nil
+}
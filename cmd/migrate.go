@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/besrabasant/valuesctl/internal/fileutil"
+	"github.com/besrabasant/valuesctl/internal/migrate"
+	"github.com/spf13/cobra"
+)
+
+var (
+	migrateSchemaPath string
+	migrateCfgPath    string
+	migrateOut        string
+	migrateTo         int
+	migrateBackup     bool
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Upgrade a config to a newer schema version using the schema's x-migrations steps",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			plan, err := migrate.LoadPlan(migrateSchemaPath)
+			if err != nil {
+				return err
+			}
+			oldYAML, err := fileutil.ReadFile(migrateCfgPath)
+			if err != nil {
+				return err
+			}
+			newYAML, res, err := plan.Apply(oldYAML, migrateTo)
+			if err != nil {
+				return err
+			}
+			if res.UpToDate() {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s: already at schema version %d\n", migrateCfgPath, res.To)
+				return nil
+			}
+
+			for _, m := range res.Applied {
+				fmt.Fprintf(cmd.OutOrStdout(), "applied migration %d", m.Version)
+				if m.Description != "" {
+					fmt.Fprintf(cmd.OutOrStdout(), ": %s", m.Description)
+				}
+				fmt.Fprintln(cmd.OutOrStdout())
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s: schema version %d -> %d\n", migrateCfgPath, res.From, res.To)
+
+			// write output (in place by default) with optional backup
+			target := migrateOut
+			if target == "" {
+				target = migrateCfgPath
+				if migrateBackup {
+					if err := fileutil.WriteFileAtomic(migrateCfgPath+".bak", oldYAML); err != nil {
+						return fmt.Errorf("write backup: %w", err)
+					}
+				}
+			}
+			return fileutil.WriteFileAtomic(target, newYAML)
+		},
+	}
+
+	cmd.Flags().StringVarP(&migrateSchemaPath, "schema", "s", "config.schema.yaml", "path to schema holding x-schema-version and x-migrations")
+	cmd.Flags().StringVarP(&migrateCfgPath, "config", "c", "config.yaml", "path to config.yaml (also default output)")
+	cmd.Flags().StringVarP(&migrateOut, "out", "o", "", "optional different output path (default: in-place)")
+	cmd.Flags().IntVar(&migrateTo, "to", 0, "target schema version (default: latest)")
+	cmd.Flags().BoolVar(&migrateBackup, "backup", true, "write a .bak beside --config before in-place update")
+
+	rootCmd.AddCommand(cmd)
+}
@@ -1,10 +1,15 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 
+	"github.com/besrabasant/valuesctl/internal/chart"
 	"github.com/besrabasant/valuesctl/internal/fileutil"
 	"github.com/besrabasant/valuesctl/internal/patcher"
+	"github.com/besrabasant/valuesctl/internal/policy"
 	"github.com/besrabasant/valuesctl/internal/schema"
 	"github.com/besrabasant/valuesctl/internal/tmpl"
 	"github.com/spf13/cobra"
@@ -18,17 +23,41 @@ var (
 	backup     bool
 	schemaPath string
 	validate   bool
+
+	valuesSchemaPath string
+	chartDir         string
+	valuesFile       string
+	subchart         string
+	policyDir        string
 )
+
 func init() {
 	cmd := &cobra.Command{
 		Use:   "patch",
 		Short: "Patch an existing values.yaml using template + config (schema-first; opt-in defaults)",
+		// policy denials and validation failures are reported, not usage errors
+		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// Optional: validate config against schema
-			if validate && schemaPath != "" {
-				if err := schema.ValidateYAMLWithSchema(schemaPath, cfgPath); err != nil {
-					return fmt.Errorf("config validation failed: %w", err)
+			// 0) chart directory: infer --file and discover values.schema.json
+			var c *chart.Chart
+			if chartDir != "" {
+				var err error
+				if c, err = chart.Open(chartDir); err != nil {
+					return err
+				}
+				if subchart != "" {
+					if err := c.CheckSubchart(subchart); err != nil {
+						return err
+					}
+				}
+				if !cmd.Flags().Changed("file") {
+					filePath = c.ValuesPath(valuesFile)
 				}
+				if !cmd.Flags().Changed("values-schema") {
+					valuesSchemaPath = "auto"
+				}
+			} else if valuesFile != "" {
+				return fmt.Errorf("--values-file requires --chart (use --file for a standalone values file)")
 			}
 
 			// 1) read old values
@@ -37,11 +66,18 @@ func init() {
 				return err
 			}
 
-			// 2) load config as map, optionally applying schema defaults
-			data, err := schema.LoadConfigWithSchemaDefaults(schemaPath, cfgPath, schemaPath != "")
+			// 2) load config as map: migrate renamed keys, optionally validate
+			//    against the schema, then apply schema defaults
+			data, deprecations, err := schema.PrepareConfig(schemaPath, cfgPath, schema.ConfigOptions{
+				Validate:      validate,
+				ApplyDefaults: schemaPath != "",
+			})
 			if err != nil {
 				return err
 			}
+			if err := reportDeprecations(cmd, deprecations); err != nil {
+				return err
+			}
 
 			// 3) render desired from template + (data map)
 			desiredYAML, err := tmpl.RenderWithData(tplPath, data)
@@ -49,13 +85,42 @@ func init() {
 				return err
 			}
 
-			// 4) compute merge patch & apply
-			newYAML, err := patcher.MergePatchYAML(oldYAML, desiredYAML)
+			// 4) compute merge patch & apply (to the subchart's section only, if set)
+			var newYAML []byte
+			if subchart != "" {
+				newYAML, err = patcher.MergePatchYAMLSection(oldYAML, desiredYAML, subchart)
+			} else {
+				newYAML, err = patcher.MergePatchYAML(oldYAML, desiredYAML)
+			}
 			if err != nil {
 				return err
 			}
 
-			// 5) write output (in place by default) with optional backup
+			// 5) optionally validate the result against the chart's values schemas
+			checks, err := resolveValuesSchemas(valuesSchemaPath, c, filePath, subchart)
+			if err != nil {
+				return err
+			}
+			for _, vc := range checks {
+				checked := newYAML
+				if vc.section != "" {
+					if checked, err = patcher.Section(newYAML, vc.section); err != nil {
+						return err
+					}
+				}
+				if err := schema.ValidateValues(vc.path, checked); err != nil {
+					return fmt.Errorf("values validation failed (%s): %w", vc.path, err)
+				}
+			}
+
+			// 6) optionally check policies on the final values before writing
+			if policyDir != "" {
+				if err := checkPolicies(cmd, policyDir, newYAML); err != nil {
+					return err
+				}
+			}
+
+			// 7) write output (in place by default) with optional backup
 			target := outPath
 			if target == "" {
 				target = filePath
@@ -77,6 +142,85 @@ func init() {
 
 	cmd.Flags().StringVarP(&schemaPath, "schema", "s", "", "optional JSON Schema (YAML or JSON) for validation/defaults")
 	cmd.Flags().BoolVar(&validate, "validate", false, "validate --config against --schema before patching")
+	cmd.Flags().StringVar(&valuesSchemaPath, "values-schema", "", `validate the patched values against a values.schema.json path, or "auto" to use the chart's (and --subchart's) or one beside --file if present`)
+	cmd.Flags().StringVar(&chartDir, "chart", "", "Helm chart directory: --file defaults to its values.yaml and --values-schema to auto")
+	cmd.Flags().StringVar(&valuesFile, "values-file", "", "values file inside --chart to patch instead of values.yaml (e.g. values-prod.yaml)")
+	cmd.Flags().StringVar(&subchart, "subchart", "", "patch only the NAME: section of the values file (Helm subchart values)")
+	cmd.Flags().BoolVar(&strictDeprecations, "strict-deprecations", false, "treat deprecated or renamed keys in --config as errors")
+	cmd.Flags().StringVar(&policyDir, "policy", "", "directory of policy files checked against the patched values; deny findings block the write")
 
 	rootCmd.AddCommand(cmd)
-}
\ No newline at end of file
+}
+
+// valuesSchemaCheck is a values schema and the section of the values file it
+// applies to ("" for the whole file).
+type valuesSchemaCheck struct {
+	path    string
+	section string
+}
+
+// resolveValuesSchemas maps the --values-schema flag to the schemas to check:
+// "" disables validation, a path checks the patched values (the --subchart
+// section, if set) and "auto" uses the chart's values.schema.json for the
+// whole file plus, with --subchart, the subchart's for its section, when they
+// exist. c is the --chart, or nil for the directory of the values file.
+func resolveValuesSchemas(flag string, c *chart.Chart, valuesPath, subchart string) ([]valuesSchemaCheck, error) {
+	switch flag {
+	case "":
+		return nil, nil
+	case "auto":
+	default:
+		return []valuesSchemaCheck{{path: flag, section: subchart}}, nil
+	}
+	if c == nil {
+		dir := filepath.Dir(valuesPath)
+		if _, err := os.Stat(filepath.Join(dir, "Chart.yaml")); err == nil {
+			if c, err = chart.Open(dir); err != nil {
+				return nil, err
+			}
+		} else if errors.Is(err, os.ErrNotExist) {
+			c = &chart.Chart{Dir: dir} // not a chart: charts/<subchart>/ by name
+		} else {
+			return nil, err
+		}
+	}
+	parent, sub, err := c.ValuesSchemas(subchart)
+	if err != nil {
+		return nil, err
+	}
+	var checks []valuesSchemaCheck
+	if parent != "" {
+		checks = append(checks, valuesSchemaCheck{path: parent})
+	}
+	if sub != "" {
+		checks = append(checks, valuesSchemaCheck{path: sub, section: subchart})
+	}
+	return checks, nil
+}
+
+// checkPolicies evaluates the policies in dir against the patched values,
+// prints a report to stderr and fails if any deny rule is violated.
+func checkPolicies(cmd *cobra.Command, dir string, values []byte) error {
+	files, err := policy.Load(dir)
+	if err != nil {
+		return err
+	}
+	findings, err := policy.Evaluate(files, values)
+	if err != nil {
+		return err
+	}
+	rules := 0
+	for _, f := range files {
+		rules += len(f.Rules)
+	}
+	denied := policy.Denied(findings)
+	w := cmd.ErrOrStderr()
+	fmt.Fprintf(w, "policy: %d rules in %d files, %d denied, %d warnings\n", rules, len(files), denied, len(findings)-denied)
+	for _, f := range findings {
+		fmt.Fprintf(w, "  %s\n", f)
+	}
+	if denied > 0 {
+		return fmt.Errorf("policy check failed: %d denied; values not written", denied)
+	}
+	return nil
+}
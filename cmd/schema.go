@@ -0,0 +1,12 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Inspect and compare config schemas",
+}
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/besrabasant/valuesctl/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:          "diff OLD NEW",
+		Short:        "Report schema changes and fail if any of them are breaking",
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			changes, err := schema.DiffSchemas(args[0], args[1])
+			if err != nil {
+				return err
+			}
+			breaking := 0
+			for _, c := range changes {
+				label := "non-breaking"
+				if c.Breaking {
+					label = "BREAKING"
+					breaking++
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%-13s %s\n", label, c)
+			}
+			if breaking > 0 {
+				return fmt.Errorf("%d breaking change(s) in %s", breaking, args[1])
+			}
+			if len(changes) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "no changes")
+			}
+			return nil
+		},
+	}
+
+	schemaCmd.AddCommand(cmd)
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"github.com/besrabasant/valuesctl/internal/fileutil"
+	"github.com/besrabasant/valuesctl/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+var (
+	helmSchemaPath string
+	helmTplPath    string
+	helmOut        string
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "helm",
+		Short: "Export a Helm values.schema.json for the values.yaml rendered by the template",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out, err := schema.BuildHelmValuesSchema(helmSchemaPath, helmTplPath)
+			if err != nil {
+				return err
+			}
+			return fileutil.WriteFileAtomic(helmOut, out)
+		},
+	}
+
+	cmd.Flags().StringVarP(&helmSchemaPath, "schema", "s", "config.schema.yaml", "path to config YAML JSON-Schema")
+	cmd.Flags().StringVarP(&helmTplPath, "template", "t", "template.tmpl", "path to Go text/template for values.yaml")
+	cmd.Flags().StringVarP(&helmOut, "out", "o", "values.schema.json", "output values.schema.json path")
+
+	schemaCmd.AddCommand(cmd)
+}
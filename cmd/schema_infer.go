@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"github.com/besrabasant/valuesctl/internal/fileutil"
+	"github.com/besrabasant/valuesctl/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+var (
+	inferValuesPath string
+	inferOut        string
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "infer",
+		Short: "Draft a YAML JSON-Schema from an existing values.yaml (comments become descriptions)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			y, err := schema.InferSchemaFromYAML(inferValuesPath)
+			if err != nil {
+				return err
+			}
+			return fileutil.WriteFileAtomic(inferOut, y)
+		},
+	}
+
+	cmd.Flags().StringVarP(&inferValuesPath, "file", "f", "values.yaml", "path to values.yaml to infer from")
+	cmd.Flags().StringVarP(&inferOut, "out", "o", "config.schema.yaml", "output schema path")
+
+	schemaCmd.AddCommand(cmd)
+}
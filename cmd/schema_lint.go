@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/besrabasant/valuesctl/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:          "lint SCHEMA",
+		Short:        "Check a YAML JSON-Schema for authoring mistakes",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			issues, err := schema.LintSchema(args[0])
+			if err != nil {
+				return err
+			}
+			for _, i := range issues {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s:%s\n", args[0], i)
+			}
+			if len(issues) > 0 {
+				return fmt.Errorf("%d issue(s) in %s", len(issues), args[0])
+			}
+			return nil
+		},
+	}
+
+	schemaCmd.AddCommand(cmd)
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/besrabasant/valuesctl/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+var (
+	validateSchemaPath string
+	validateCfgPath    string
+	strictDeprecations bool
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate a config against a YAML JSON-Schema (renamed keys migrated, deprecations reported)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, deprecations, err := schema.PrepareConfig(validateSchemaPath, validateCfgPath, schema.ConfigOptions{Validate: true})
+			if err != nil {
+				return err
+			}
+			if err := reportDeprecations(cmd, deprecations); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s: valid\n", validateCfgPath)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&validateSchemaPath, "schema", "s", "config.schema.yaml", "path to YAML JSON-Schema")
+	cmd.Flags().StringVarP(&validateCfgPath, "config", "c", "config.yaml", "path to config.yaml")
+	cmd.Flags().BoolVar(&strictDeprecations, "strict-deprecations", false, "treat deprecated or renamed keys as errors")
+
+	rootCmd.AddCommand(cmd)
+}
+
+// reportDeprecations prints deprecation warnings to stderr, or fails when
+// --strict-deprecations is set.
+func reportDeprecations(cmd *cobra.Command, deprecations []schema.Deprecation) error {
+	if len(deprecations) == 0 {
+		return nil
+	}
+	if strictDeprecations {
+		return fmt.Errorf("deprecated config keys:\n%s", schema.FormatDeprecations(deprecations))
+	}
+	for _, d := range deprecations {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: %s\n", d)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+// Package celx compiles the CEL expressions used by schema x-rules and
+// policy files, sharing one environment setup and program cache.
+package celx
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+)
+
+var (
+	mu       sync.Mutex
+	programs = map[string]cel.Program{}
+)
+
+// Compile compiles a CEL rule over the given dynamically typed variables,
+// with the strings extension and cross-type numeric comparisons enabled.
+// Programs are cached by variables and expression.
+func Compile(expr string, vars ...string) (cel.Program, error) {
+	key := strings.Join(vars, ",") + "\x00" + expr
+	mu.Lock()
+	defer mu.Unlock()
+	if prg, ok := programs[key]; ok {
+		return prg, nil
+	}
+	opts := []cel.EnvOption{ext.Strings(), cel.CrossTypeNumericComparisons(true)}
+	for _, name := range vars {
+		opts = append(opts, cel.Variable(name, cel.DynType))
+	}
+	env, err := cel.NewEnv(opts...)
+	if err != nil {
+		return nil, err
+	}
+	ast, iss := env.Compile(expr)
+	if iss.Err() != nil {
+		return nil, fmt.Errorf("rule %q: %w", expr, iss.Err())
+	}
+	prg, err := env.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("rule %q: %w", expr, err)
+	}
+	programs[key] = prg
+	return prg, nil
+}
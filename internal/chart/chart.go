@@ -0,0 +1,114 @@
+package chart
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Chart is a Helm chart directory (one containing Chart.yaml).
+type Chart struct {
+	Dir          string
+	Name         string       `json:"name"`
+	Dependencies []Dependency `json:"dependencies,omitempty"`
+}
+
+// Dependency is an entry of Chart.yaml "dependencies".
+type Dependency struct {
+	Name  string `json:"name"`
+	Alias string `json:"alias,omitempty"`
+}
+
+// Open reads dir/Chart.yaml.
+func Open(dir string) (*Chart, error) {
+	raw, err := os.ReadFile(filepath.Join(dir, "Chart.yaml"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%s is not a Helm chart (no Chart.yaml)", dir)
+		}
+		return nil, err
+	}
+	c := &Chart{}
+	if err := yaml.Unmarshal(raw, c); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", filepath.Join(dir, "Chart.yaml"), err)
+	}
+	c.Dir = dir
+	return c, nil
+}
+
+// ValuesPath returns the values file inside the chart: values.yaml by default,
+// or valuesFile (e.g. values-prod.yaml) resolved relative to the chart directory.
+func (c *Chart) ValuesPath(valuesFile string) string {
+	if valuesFile == "" {
+		valuesFile = "values.yaml"
+	}
+	if filepath.IsAbs(valuesFile) {
+		return valuesFile
+	}
+	return filepath.Join(c.Dir, valuesFile)
+}
+
+// SubchartDir returns the directory of subchart name (a dependency name or
+// alias) under charts/. The directory is named after the dependency, so an
+// alias maps to its dependency's name.
+func (c *Chart) SubchartDir(name string) string {
+	for _, d := range c.Dependencies {
+		if d.Alias == name && d.Name != "" {
+			name = d.Name
+			break
+		}
+	}
+	return filepath.Join(c.Dir, "charts", name)
+}
+
+// ValuesSchemas returns the chart's values.schema.json and, when subchart is
+// set, the subchart's; a schema that does not exist is returned as "".
+func (c *Chart) ValuesSchemas(subchart string) (parent, sub string, err error) {
+	if parent, err = existing(filepath.Join(c.Dir, "values.schema.json")); err != nil {
+		return "", "", err
+	}
+	if subchart != "" {
+		if sub, err = existing(filepath.Join(c.SubchartDir(subchart), "values.schema.json")); err != nil {
+			return "", "", err
+		}
+	}
+	return parent, sub, nil
+}
+
+func existing(p string) (string, error) {
+	if _, err := os.Stat(p); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", err
+	}
+	return p, nil
+}
+
+// CheckSubchart verifies that name is a dependency (by alias, or by name when
+// it has none) or a directory under charts/, so a typo does not create a stray
+// values section. Helm reads an aliased dependency's values under its alias
+// only, so its chart name is rejected.
+func (c *Chart) CheckSubchart(name string) error {
+	var aliases []string
+	for _, d := range c.Dependencies {
+		if d.Alias == name || (d.Name == name && d.Alias == "") {
+			return nil
+		}
+		if d.Name == name {
+			aliases = append(aliases, strconv.Quote(d.Alias))
+		}
+	}
+	if len(aliases) > 0 {
+		return fmt.Errorf("chart %q has subchart %q aliased as %s; use the alias, Helm ignores values under the chart name", c.Name, name, strings.Join(aliases, " or "))
+	}
+	if fi, err := os.Stat(filepath.Join(c.Dir, "charts", name)); err == nil && fi.IsDir() {
+		return nil
+	}
+	return fmt.Errorf("chart %q has no subchart %q (not in Chart.yaml dependencies or charts/)", c.Name, name)
+}
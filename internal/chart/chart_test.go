@@ -0,0 +1,115 @@
+package chart
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValuesSchemas(t *testing.T) {
+	dir := t.TempDir()
+	write := func(rel, content string) {
+		t.Helper()
+		p := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("Chart.yaml", "name: api\ndependencies:\n  - name: redis\n    alias: cache\n  - name: postgresql\n")
+	write("values.schema.json", "{}")
+	write("charts/redis/values.schema.json", "{}")
+	write("charts/postgresql/Chart.yaml", "name: postgresql\n")
+
+	c, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		subchart   string
+		wantSubDir string
+		wantSub    string
+	}{
+		{name: "no subchart"},
+		{name: "by name", subchart: "redis", wantSubDir: "charts/redis", wantSub: "charts/redis/values.schema.json"},
+		{name: "by alias", subchart: "cache", wantSubDir: "charts/redis", wantSub: "charts/redis/values.schema.json"},
+		{name: "subchart without a schema", subchart: "postgresql", wantSubDir: "charts/postgresql"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent, sub, err := c.ValuesSchemas(tt.subchart)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := filepath.Join(dir, "values.schema.json"); parent != want {
+				t.Errorf("parent = %q, want %q", parent, want)
+			}
+			wantSub := ""
+			if tt.wantSub != "" {
+				wantSub = filepath.Join(dir, tt.wantSub)
+			}
+			if sub != wantSub {
+				t.Errorf("sub = %q, want %q", sub, wantSub)
+			}
+			if tt.subchart != "" {
+				if got, want := c.SubchartDir(tt.subchart), filepath.Join(dir, tt.wantSubDir); got != want {
+					t.Errorf("SubchartDir = %q, want %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckSubchart(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "Chart.yaml"), []byte("name: api\ndependencies:\n  - name: redis\n    alias: cache\n  - name: pg\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, sub := range []string{"local", "redis"} {
+		if err := os.MkdirAll(filepath.Join(dir, "charts", sub), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		name    string
+		wantErr string
+	}{
+		{"cache", ""},
+		{"pg", ""},
+		{"local", ""},
+		{"redis", `chart "api" has subchart "redis" aliased as "cache"; use the alias`},
+		{"redsi", `chart "api" has no subchart "redsi"`},
+	} {
+		err := c.CheckSubchart(tt.name)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("CheckSubchart(%q) = %v", tt.name, err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("CheckSubchart(%q) = %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValuesPath(t *testing.T) {
+	c := &Chart{Dir: "charts/api"}
+	tests := []struct{ valuesFile, want string }{
+		{"", filepath.Join("charts/api", "values.yaml")},
+		{"values-prod.yaml", filepath.Join("charts/api", "values-prod.yaml")},
+		{"envs/prod.yaml", filepath.Join("charts/api", "envs/prod.yaml")},
+		{"/abs/values.yaml", "/abs/values.yaml"},
+	}
+	for _, tt := range tests {
+		if got := c.ValuesPath(tt.valuesFile); got != tt.want {
+			t.Errorf("ValuesPath(%q) = %q, want %q", tt.valuesFile, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package migrate
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	y3 "gopkg.in/yaml.v3"
+	"sigs.k8s.io/yaml"
+)
+
+// VersionKey is the top-level config key recording the schema version a config conforms to.
+const VersionKey = "schemaVersion"
+
+// Plan is the migration metadata stored alongside a schema:
+//
+//	x-schema-version: 3
+//	x-migrations:
+//	  - version: 2
+//	    steps:
+//	      - move: { from: app.name, to: app.meta.name }
+type Plan struct {
+	Version    int         `json:"x-schema-version,omitempty"`
+	Migrations []Migration `json:"x-migrations,omitempty"`
+}
+
+// Migration upgrades a config to Version (from Version-1).
+type Migration struct {
+	Version     int    `json:"version"`
+	Description string `json:"description,omitempty"`
+	Steps       []Step `json:"steps"`
+}
+
+// Step is a single declarative edit; exactly one field should be set.
+type Step struct {
+	Move      *MoveStep      `json:"move,omitempty"`
+	Rename    *RenameStep    `json:"rename,omitempty"`
+	Delete    *DeleteStep    `json:"delete,omitempty"`
+	Set       *SetStep       `json:"set,omitempty"`
+	Transform *TransformStep `json:"transform,omitempty"`
+}
+
+// MoveStep moves the key at From to To, creating intermediate objects.
+type MoveStep struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// RenameStep renames the key at Path to To, keeping its position.
+type RenameStep struct {
+	Path string `json:"path"`
+	To   string `json:"to"`
+}
+
+// DeleteStep removes the key at Path.
+type DeleteStep struct {
+	Path string `json:"path"`
+}
+
+// SetStep sets Path to Value, creating intermediate objects.
+type SetStep struct {
+	Path  string `json:"path"`
+	Value any    `json:"value"`
+}
+
+// TransformStep rewrites the value at Path. Map replaces values by lookup
+// (keys compared as strings); Template is a Go text/template executed with the
+// current value as "." whose output is parsed as YAML.
+type TransformStep struct {
+	Path     string         `json:"path"`
+	Map      map[string]any `json:"map,omitempty"`
+	Template string         `json:"template,omitempty"`
+}
+
+// LoadPlan reads "x-schema-version" and "x-migrations" from a schema (YAML or JSON).
+func LoadPlan(schemaPath string) (*Plan, error) {
+	raw, err := os.ReadFile(schemaPath)
+	if err != nil {
+		return nil, fmt.Errorf("read schema: %w", err)
+	}
+	var p Plan
+	if err := yaml.Unmarshal(raw, &p); err != nil {
+		return nil, fmt.Errorf("parse migrations: %w", err)
+	}
+	sort.SliceStable(p.Migrations, func(i, j int) bool { return p.Migrations[i].Version < p.Migrations[j].Version })
+	for i, m := range p.Migrations {
+		if m.Version < 2 {
+			return nil, fmt.Errorf("migration %d: version must be >= 2 (version 1 is the initial schema)", i)
+		}
+		if i > 0 && p.Migrations[i-1].Version == m.Version {
+			return nil, fmt.Errorf("duplicate migration for version %d", m.Version)
+		}
+	}
+	return &p, nil
+}
+
+// Latest returns the target version: "x-schema-version" if set, else the
+// highest migration version, else 1.
+func (p *Plan) Latest() int {
+	v := 1
+	if n := len(p.Migrations); n > 0 {
+		v = p.Migrations[n-1].Version
+	}
+	if p.Version > v {
+		v = p.Version
+	}
+	return v
+}
+
+// Result describes what Apply did.
+type Result struct {
+	From    int
+	To      int
+	Applied []Migration
+}
+
+// UpToDate reports whether the config was already at the target version.
+func (r *Result) UpToDate() bool {
+	return r.From == r.To
+}
+
+// Apply upgrades cfgYAML to version to (0 means p.Latest()) by running every
+// migration newer than the config's schemaVersion marker (missing marker means
+// version 1). Comments and key order in the config are preserved. A config
+// already at version to is returned unchanged (res.UpToDate reports it).
+func (p *Plan) Apply(cfgYAML []byte, to int) ([]byte, *Result, error) {
+	if to == 0 {
+		to = p.Latest()
+	}
+
+	var doc y3.Node
+	if err := y3.Unmarshal(cfgYAML, &doc); err != nil {
+		return nil, nil, fmt.Errorf("parse config: %w", err)
+	}
+	if doc.Kind == 0 {
+		// empty, or only comments (which the parser drops): keep them as the
+		// document's head comment
+		doc = y3.Node{Kind: y3.DocumentNode, HeadComment: strings.TrimSpace(string(cfgYAML)), Content: []*y3.Node{{Kind: y3.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != y3.MappingNode {
+		return nil, nil, fmt.Errorf("config root must be a mapping")
+	}
+
+	from := 1
+	if _, v := lookup(root, VersionKey); v != nil {
+		n, err := strconv.Atoi(v.Value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: not an integer: %q", VersionKey, v.Value)
+		}
+		from = n
+	}
+	if from > to {
+		return nil, nil, fmt.Errorf("config is at schema version %d, newer than target %d", from, to)
+	}
+
+	res := &Result{From: from, To: to}
+	if from == to {
+		return cfgYAML, res, nil
+	}
+	for _, m := range p.Migrations {
+		if m.Version <= from || m.Version > to {
+			continue
+		}
+		for i, st := range m.Steps {
+			if err := applyStep(root, st); err != nil {
+				return nil, nil, fmt.Errorf("migration %d step %d: %w", m.Version, i+1, err)
+			}
+		}
+		res.Applied = append(res.Applied, m)
+	}
+
+	if err := setPath(root, []string{VersionKey}, scalarNode(strconv.Itoa(to), "!!int")); err != nil {
+		return nil, nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := y3.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, nil, fmt.Errorf("yaml encode: %w", err)
+	}
+	return buf.Bytes(), res, nil
+}
+
+func applyStep(root *y3.Node, st Step) error {
+	switch {
+	case st.Move != nil:
+		from, to := splitPath(st.Move.From), splitPath(st.Move.To)
+		parent, idx := lookupPair(root, from)
+		if parent == nil {
+			return nil // nothing to move
+		}
+		if _, v := lookupPath(root, to); v != nil {
+			return fmt.Errorf("move %s -> %s: destination already exists", st.Move.From, st.Move.To)
+		}
+		key, val := parent.Content[idx], parent.Content[idx+1]
+		parent.Content = append(parent.Content[:idx], parent.Content[idx+2:]...)
+		key.Value = to[len(to)-1]
+		return insertPair(root, to, key, val)
+
+	case st.Rename != nil:
+		parent, idx := lookupPair(root, splitPath(st.Rename.Path))
+		if parent == nil {
+			return nil
+		}
+		if k, _ := lookup(parent, st.Rename.To); k != nil {
+			return fmt.Errorf("rename %s -> %s: key already exists", st.Rename.Path, st.Rename.To)
+		}
+		parent.Content[idx].Value = st.Rename.To
+
+	case st.Delete != nil:
+		parent, idx := lookupPair(root, splitPath(st.Delete.Path))
+		if parent != nil {
+			parent.Content = append(parent.Content[:idx], parent.Content[idx+2:]...)
+		}
+
+	case st.Set != nil:
+		var val y3.Node
+		if err := val.Encode(st.Set.Value); err != nil {
+			return fmt.Errorf("set %s: %w", st.Set.Path, err)
+		}
+		return setPath(root, splitPath(st.Set.Path), &val)
+
+	case st.Transform != nil:
+		return transform(root, st.Transform)
+
+	default:
+		return fmt.Errorf("step has no operation (expected move, rename, delete, set or transform)")
+	}
+	return nil
+}
+
+func transform(root *y3.Node, t *TransformStep) error {
+	parent, idx := lookupPair(root, splitPath(t.Path))
+	if parent == nil {
+		return nil
+	}
+	cur := parent.Content[idx+1]
+
+	var val any
+	if err := cur.Decode(&val); err != nil {
+		return fmt.Errorf("transform %s: %w", t.Path, err)
+	}
+	if t.Map != nil {
+		if repl, ok := t.Map[fmt.Sprintf("%v", val)]; ok {
+			val = repl
+		}
+	}
+	if t.Template != "" {
+		tpl, err := template.New(t.Path).Option("missingkey=error").Parse(t.Template)
+		if err != nil {
+			return fmt.Errorf("transform %s: %w", t.Path, err)
+		}
+		var buf bytes.Buffer
+		if err := tpl.Execute(&buf, val); err != nil {
+			return fmt.Errorf("transform %s: %w", t.Path, err)
+		}
+		if err := y3.Unmarshal(buf.Bytes(), &val); err != nil {
+			return fmt.Errorf("transform %s: template output is not YAML: %w", t.Path, err)
+		}
+	}
+
+	var next y3.Node
+	if err := next.Encode(val); err != nil {
+		return fmt.Errorf("transform %s: %w", t.Path, err)
+	}
+	next.HeadComment, next.LineComment, next.FootComment = cur.HeadComment, cur.LineComment, cur.FootComment
+	parent.Content[idx+1] = &next
+	return nil
+}
@@ -0,0 +1,152 @@
+package migrate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	plan := &Plan{
+		Version: 3,
+		Migrations: []Migration{
+			{Version: 2, Steps: []Step{
+				{Move: &MoveStep{From: "app.name", To: "app.meta.name"}},
+				{Rename: &RenameStep{Path: "tlsSecret", To: "tlsSecretName"}},
+				{Delete: &DeleteStep{Path: "legacy"}},
+			}},
+			{Version: 3, Steps: []Step{
+				{Set: &SetStep{Path: "app.tier", Value: "standard"}},
+				{Transform: &TransformStep{Path: "app.version", Template: "v{{ . }}"}},
+				{Transform: &TransformStep{Path: "env", Map: map[string]any{"dev": "development"}}},
+			}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		in      string
+		to      int
+		want    string
+		applied int
+		wantErr string
+	}{
+		{
+			name: "all migrations",
+			in: `# my config
+app:
+  name: api # the name
+  version: 1.2
+tlsSecret: s
+legacy: true
+env: dev
+`,
+			want: `# my config
+app:
+  version: v1.2
+  meta:
+    name: api # the name
+  tier: standard
+tlsSecretName: s
+env: development
+schemaVersion: 3
+`,
+			applied: 2,
+		},
+		{
+			name:    "up to an intermediate version",
+			in:      "tlsSecret: s\n",
+			to:      2,
+			want:    "tlsSecretName: s\nschemaVersion: 2\n",
+			applied: 1,
+		},
+		{
+			name:    "only newer migrations run",
+			in:      "schemaVersion: 2\nenv: dev\ntlsSecret: kept\n",
+			want:    "schemaVersion: 3\nenv: development\ntlsSecret: kept\napp:\n  tier: standard\n",
+			applied: 1,
+		},
+		{
+			name: "already at target is unchanged",
+			in:   "schemaVersion: 3 # pinned\nenv: dev\n",
+			want: "schemaVersion: 3 # pinned\nenv: dev\n",
+		},
+		{
+			name:    "comment-only config keeps its comments",
+			in:      "# filled in later\n# by ops\n",
+			to:      2,
+			want:    "# filled in later\n# by ops\n\nschemaVersion: 2\n",
+			applied: 1,
+		},
+		{
+			name:    "newer than target",
+			in:      "schemaVersion: 4\n",
+			wantErr: "newer than target",
+		},
+		{
+			name:    "move onto existing key",
+			in:      "app:\n  name: a\n  meta:\n    name: b\n",
+			wantErr: "destination already exists",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, res, err := plan.Apply([]byte(tt.in), tt.to)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(out) != tt.want {
+				t.Errorf("output:\n%s\nwant:\n%s", out, tt.want)
+			}
+			if len(res.Applied) != tt.applied {
+				t.Errorf("applied %d migrations, want %d", len(res.Applied), tt.applied)
+			}
+			if res.UpToDate() != (tt.applied == 0) {
+				t.Errorf("UpToDate = %v", res.UpToDate())
+			}
+		})
+	}
+}
+
+func TestLoadPlan(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		latest  int
+		wantErr string
+	}{
+		{name: "no migrations", schema: "type: object\n", latest: 1},
+		{name: "sorted by version", schema: "x-migrations:\n  - {version: 3, steps: []}\n  - {version: 2, steps: []}\n", latest: 3},
+		{name: "explicit version", schema: "x-schema-version: 5\nx-migrations:\n  - {version: 2, steps: []}\n", latest: 5},
+		{name: "version 1", schema: "x-migrations:\n  - {version: 1, steps: []}\n", wantErr: "must be >= 2"},
+		{name: "duplicate", schema: "x-migrations:\n  - {version: 2, steps: []}\n  - {version: 2, steps: []}\n", wantErr: "duplicate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := filepath.Join(t.TempDir(), "schema.yaml")
+			if err := os.WriteFile(p, []byte(tt.schema), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			plan, err := LoadPlan(p)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := plan.Latest(); got != tt.latest {
+				t.Errorf("Latest() = %d, want %d", got, tt.latest)
+			}
+		})
+	}
+}
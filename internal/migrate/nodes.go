@@ -0,0 +1,124 @@
+package migrate
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	y3 "gopkg.in/yaml.v3"
+)
+
+// splitPath turns "a.b.0.c" into its segments; numeric segments index sequences.
+func splitPath(p string) []string {
+	return strings.Split(p, ".")
+}
+
+// lookup finds key in a mapping node, returning the key and value nodes.
+func lookup(m *y3.Node, key string) (*y3.Node, *y3.Node) {
+	if m == nil || m.Kind != y3.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i], m.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// child steps one path segment into a mapping or sequence node.
+func child(n *y3.Node, seg string) *y3.Node {
+	switch n.Kind {
+	case y3.MappingNode:
+		_, v := lookup(n, seg)
+		return v
+	case y3.SequenceNode:
+		i, err := strconv.Atoi(seg)
+		if err != nil || i < 0 || i >= len(n.Content) {
+			return nil
+		}
+		return n.Content[i]
+	}
+	return nil
+}
+
+// lookupPath returns the node at path, or nil.
+func lookupPath(root *y3.Node, path []string) (*y3.Node, *y3.Node) {
+	var key *y3.Node
+	cur := root
+	for _, seg := range path {
+		if cur.Kind == y3.MappingNode {
+			key, cur = lookup(cur, seg)
+		} else {
+			key, cur = nil, child(cur, seg)
+		}
+		if cur == nil {
+			return nil, nil
+		}
+	}
+	return key, cur
+}
+
+// lookupPair returns the mapping holding the last path segment and the index
+// of its key node, or nil if the path does not exist.
+func lookupPair(root *y3.Node, path []string) (*y3.Node, int) {
+	parent := root
+	if len(path) > 1 {
+		_, parent = lookupPath(root, path[:len(path)-1])
+	}
+	if parent == nil || parent.Kind != y3.MappingNode {
+		return nil, 0
+	}
+	last := path[len(path)-1]
+	for i := 0; i+1 < len(parent.Content); i += 2 {
+		if parent.Content[i].Value == last {
+			return parent, i
+		}
+	}
+	return nil, 0
+}
+
+// ensureMapping walks path, creating empty mappings for missing segments.
+func ensureMapping(root *y3.Node, path []string) (*y3.Node, error) {
+	cur := root
+	for i, seg := range path {
+		next := child(cur, seg)
+		if next == nil {
+			if cur.Kind != y3.MappingNode {
+				return nil, fmt.Errorf("%s: cannot create key inside a non-mapping", strings.Join(path[:i+1], "."))
+			}
+			next = &y3.Node{Kind: y3.MappingNode}
+			cur.Content = append(cur.Content, scalarNode(seg, "!!str"), next)
+		}
+		cur = next
+	}
+	if cur.Kind != y3.MappingNode {
+		return nil, fmt.Errorf("%s: not a mapping", strings.Join(path, "."))
+	}
+	return cur, nil
+}
+
+// insertPair appends key/val under the parent of path, creating intermediate mappings.
+func insertPair(root *y3.Node, path []string, key, val *y3.Node) error {
+	parent, err := ensureMapping(root, path[:len(path)-1])
+	if err != nil {
+		return err
+	}
+	parent.Content = append(parent.Content, key, val)
+	return nil
+}
+
+// setPath replaces the value at path (keeping its comments) or inserts it.
+func setPath(root *y3.Node, path []string, val *y3.Node) error {
+	if parent, idx := lookupPair(root, path); parent != nil {
+		cur := parent.Content[idx+1]
+		val.HeadComment, val.LineComment, val.FootComment = cur.HeadComment, cur.LineComment, cur.FootComment
+		parent.Content[idx+1] = val
+		return nil
+	}
+	return insertPair(root, path, scalarNode(path[len(path)-1], "!!str"), val)
+}
+
+func scalarNode(value, tag string) *y3.Node {
+	return &y3.Node{Kind: y3.ScalarNode, Tag: tag, Value: value}
+}
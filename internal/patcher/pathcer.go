@@ -1,6 +1,8 @@
 package patcher
 
 import (
+	"encoding/json"
+
 	jsonpatch "github.com/evanphx/json-patch/v5"
 	"sigs.k8s.io/yaml"
 )
@@ -24,4 +26,52 @@ func MergePatchYAML(oldYAML, desiredYAML []byte) ([]byte, error) {
 		return nil, err
 	}
 	return yaml.JSONToYAML(newJSON)
-}
\ No newline at end of file
+}
+
+// MergePatchYAMLSection applies the merge patch from old->desired to the
+// top-level section of the old values only (e.g. a Helm subchart's values),
+// leaving every other key untouched.
+func MergePatchYAMLSection(oldYAML, desiredYAML []byte, section string) ([]byte, error) {
+	var doc map[string]any
+	if err := yaml.Unmarshal(oldYAML, &doc); err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		doc = map[string]any{}
+	}
+	curJSON := []byte("{}")
+	if cur, ok := doc[section]; ok && cur != nil {
+		b, err := json.Marshal(cur)
+		if err != nil {
+			return nil, err
+		}
+		curJSON = b
+	}
+	desiredJSON, err := yaml.YAMLToJSON(desiredYAML)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := jsonpatch.CreateMergePatch(curJSON, desiredJSON)
+	if err != nil {
+		return nil, err
+	}
+	newJSON, err := jsonpatch.MergePatch(curJSON, patch)
+	if err != nil {
+		return nil, err
+	}
+	var sec any
+	if err := json.Unmarshal(newJSON, &sec); err != nil {
+		return nil, err
+	}
+	doc[section] = sec
+	return yaml.Marshal(doc)
+}
+
+// Section returns the top-level section of a values document as YAML.
+func Section(valuesYAML []byte, section string) ([]byte, error) {
+	var doc map[string]any
+	if err := yaml.Unmarshal(valuesYAML, &doc); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(doc[section])
+}
@@ -0,0 +1,349 @@
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/besrabasant/valuesctl/internal/celx"
+	y3 "gopkg.in/yaml.v3"
+	"sigs.k8s.io/yaml"
+)
+
+// Severities of a rule: a denied finding blocks the write, a warning is only reported.
+const (
+	Deny = "deny"
+	Warn = "warn"
+)
+
+// File is a policy file: a list of rules checked against the final values.
+//
+//	rules:
+//	  - name: pinned-image
+//	    message: "image tags must be pinned"
+//	    deny: { path: image.tag, equals: latest }
+//	  - name: limits
+//	    severity: warn
+//	    require: { path: resources.limits }
+//	  - name: ha
+//	    rule: "values.replicaCount >= 2"
+type File struct {
+	Path  string `json:"-"`
+	Rules []Rule `json:"rules"`
+}
+
+// Rule is a single check; exactly one of Deny, Require or CEL should be set.
+type Rule struct {
+	Name     string        `json:"name,omitempty"`
+	Severity string        `json:"severity,omitempty"` // deny (default) or warn
+	Message  string        `json:"message,omitempty"`
+	Deny     *DenyCheck    `json:"deny,omitempty"`
+	Require  *RequireCheck `json:"require,omitempty"`
+	CEL      string        `json:"rule,omitempty"`
+}
+
+// DenyCheck flags the value at Path when it equals Equals or matches the
+// regular expression Matches; with neither, any (non-null) value is flagged.
+type DenyCheck struct {
+	Path    string `json:"path"`
+	Equals  any    `json:"equals,omitempty"`
+	Matches string `json:"matches,omitempty"`
+
+	hasEquals bool // "equals" was given, so Equals is set even if false or 0
+}
+
+// UnmarshalJSON records whether "equals" is present: null values are never
+// flagged, so Load rejects "equals: null" instead of treating it as unset.
+func (d *DenyCheck) UnmarshalJSON(b []byte) error {
+	type plain DenyCheck
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode((*plain)(d)); err != nil {
+		return err
+	}
+	_, d.hasEquals = fields["equals"]
+	return nil
+}
+
+// RequireCheck flags Path when it is missing or null.
+type RequireCheck struct {
+	Path string `json:"path"`
+}
+
+// Finding is a rule that does not hold, at a concrete values path.
+type Finding struct {
+	File     string
+	Rule     string
+	Severity string
+	Path     string
+	Message  string
+}
+
+func (f Finding) String() string {
+	s := fmt.Sprintf("%-4s %s: %s", strings.ToUpper(f.Severity), f.File, f.Rule)
+	if f.Path != "" {
+		s += ": " + f.Path
+	}
+	return s + ": " + f.Message
+}
+
+// Load reads the policy files (*.yaml, *.yml, *.json) of dir in name order.
+func Load(dir string) ([]*File, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read policies: %w", err)
+	}
+	var files []*File
+	for _, e := range entries {
+		switch strings.ToLower(filepath.Ext(e.Name())) {
+		case ".yaml", ".yml", ".json":
+		default:
+			continue
+		}
+		if e.IsDir() {
+			continue
+		}
+		p := filepath.Join(dir, e.Name())
+		f, err := loadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no policy files (*.yaml, *.json) in %s", dir)
+	}
+	return files, nil
+}
+
+func loadFile(p string) (*File, error) {
+	raw, err := os.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	f := &File{Path: p}
+	if err := yaml.UnmarshalStrict(raw, f); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", p, err)
+	}
+	for i := range f.Rules {
+		r := &f.Rules[i]
+		if r.Name == "" {
+			r.Name = fmt.Sprintf("rule %d", i+1)
+		}
+		switch r.Severity {
+		case "":
+			r.Severity = Deny
+		case Deny, Warn:
+		default:
+			return nil, fmt.Errorf("%s: %s: severity must be deny or warn, got %q", p, r.Name, r.Severity)
+		}
+		n := 0
+		for _, set := range []bool{r.Deny != nil, r.Require != nil, r.CEL != ""} {
+			if set {
+				n++
+			}
+		}
+		if n != 1 {
+			return nil, fmt.Errorf("%s: %s: set exactly one of deny, require or rule", p, r.Name)
+		}
+		if r.Deny != nil && r.Deny.hasEquals && r.Deny.Equals == nil {
+			return nil, fmt.Errorf("%s: %s: deny.equals cannot be null (null values are never flagged; use require to flag missing ones)", p, r.Name)
+		}
+		if r.Deny != nil && r.Deny.Matches != "" {
+			if _, err := regexp.Compile(r.Deny.Matches); err != nil {
+				return nil, fmt.Errorf("%s: %s: %w", p, r.Name, err)
+			}
+		}
+		if r.CEL != "" {
+			if _, err := celx.Compile(r.CEL, "values"); err != nil {
+				return nil, fmt.Errorf("%s: %s: %w", p, r.Name, err)
+			}
+		}
+	}
+	return f, nil
+}
+
+// Evaluate checks every rule of files against a values document (YAML).
+func Evaluate(files []*File, valuesYAML []byte) ([]Finding, error) {
+	var values any
+	if err := y3.Unmarshal(valuesYAML, &values); err != nil {
+		return nil, fmt.Errorf("parse values: %w", err)
+	}
+	if values == nil {
+		values = map[string]any{}
+	}
+	var out []Finding
+	for _, f := range files {
+		name := filepath.Base(f.Path)
+		for _, r := range f.Rules {
+			fs, err := r.evaluate(values)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s: %w", name, r.Name, err)
+			}
+			for i := range fs {
+				fs[i].File, fs[i].Rule, fs[i].Severity = name, r.Name, r.Severity
+			}
+			out = append(out, fs...)
+		}
+	}
+	return out, nil
+}
+
+// Denied counts the findings with deny severity.
+func Denied(findings []Finding) int {
+	n := 0
+	for _, f := range findings {
+		if f.Severity == Deny {
+			n++
+		}
+	}
+	return n
+}
+
+func (r Rule) evaluate(values any) ([]Finding, error) {
+	msg := func(def string) string {
+		if r.Message != "" {
+			return r.Message
+		}
+		return def
+	}
+	var out []Finding
+	switch {
+	case r.Deny != nil:
+		found, _ := resolve(values, r.Deny.Path)
+		for _, m := range found {
+			if m.value == nil {
+				continue
+			}
+			switch {
+			case r.Deny.hasEquals:
+				if !sameJSON(m.value, r.Deny.Equals) {
+					continue
+				}
+			case r.Deny.Matches != "":
+				if !regexp.MustCompile(r.Deny.Matches).MatchString(fmt.Sprint(m.value)) {
+					continue
+				}
+			}
+			out = append(out, Finding{Path: m.path, Message: msg(fmt.Sprintf("value %s is not allowed", jsonString(m.value)))})
+		}
+
+	case r.Require != nil:
+		found, missing := resolve(values, r.Require.Path)
+		for _, m := range found {
+			if m.value == nil {
+				missing = append(missing, m.path)
+			}
+		}
+		sort.Strings(missing)
+		for _, p := range missing {
+			out = append(out, Finding{Path: p, Message: msg("required")})
+		}
+
+	case r.CEL != "":
+		prg, err := celx.Compile(r.CEL, "values")
+		if err != nil {
+			return nil, err
+		}
+		res, _, err := prg.Eval(map[string]any{"values": values})
+		if err != nil {
+			// like a deny or require path, a rule over keys the values lack
+			// doesn't apply
+			if strings.HasPrefix(err.Error(), "no such key") {
+				break
+			}
+			out = append(out, Finding{Message: fmt.Sprintf("rule %q: %v", r.CEL, err)})
+			break
+		}
+		if b, ok := res.Value().(bool); !ok {
+			return nil, fmt.Errorf("rule %q: must evaluate to a bool, got %v", r.CEL, res.Type())
+		} else if !b {
+			out = append(out, Finding{Message: msg(fmt.Sprintf("failed rule: %s", r.CEL))})
+		}
+	}
+	return out, nil
+}
+
+type match struct {
+	path  string
+	value any
+}
+
+// resolve looks up a dotted path in values: "*" matches every key or
+// element, a number indexes a list. It returns the values found and the
+// concrete paths that are missing (in full, e.g. "resources.limits" when
+// "resources" is absent).
+func resolve(values any, path string) (found []match, missing []string) {
+	cur := []match{{value: values}}
+	segs := strings.Split(path, ".")
+	for i, seg := range segs {
+		rest := strings.Join(segs[i:], ".")
+		var next []match
+		for _, m := range cur {
+			switch t := m.value.(type) {
+			case map[string]any:
+				if seg == "*" {
+					keys := make([]string, 0, len(t))
+					for k := range t {
+						keys = append(keys, k)
+					}
+					sort.Strings(keys)
+					for _, k := range keys {
+						next = append(next, match{join(m.path, k), t[k]})
+					}
+				} else if v, ok := t[seg]; ok {
+					next = append(next, match{join(m.path, seg), v})
+				} else {
+					missing = append(missing, join(m.path, rest))
+				}
+			case []any:
+				if seg == "*" {
+					for i, v := range t {
+						next = append(next, match{fmt.Sprintf("%s[%d]", m.path, i), v})
+					}
+				} else if i, err := strconv.Atoi(seg); err == nil && i >= 0 && i < len(t) {
+					next = append(next, match{fmt.Sprintf("%s[%d]", m.path, i), t[i]})
+				} else {
+					missing = append(missing, join(m.path, rest))
+				}
+			default:
+				if seg != "*" {
+					missing = append(missing, join(m.path, rest))
+				}
+			}
+		}
+		cur = next
+	}
+	return cur, missing
+}
+
+func join(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+// sameJSON compares two decoded values by their JSON encoding, so 2 and 2.0
+// (or values decoded by different YAML libraries) compare equal.
+func sameJSON(a, b any) bool {
+	return jsonString(a) == jsonString(b)
+}
+
+func jsonString(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
@@ -0,0 +1,144 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	const values = `image:
+  tag: latest
+replicaCount: 1
+debug: false
+port: 0
+containers:
+  - {name: app, user: root}
+  - {name: sidecar, user: "1000"}
+resources: null
+`
+	tests := []struct {
+		name   string
+		policy string
+		want   []string
+	}{
+		{
+			name:   "deny equals",
+			policy: "rules:\n  - name: pinned-image\n    message: image tags must be pinned\n    deny: {path: image.tag, equals: latest}\n",
+			want:   []string{"DENY p.yaml: pinned-image: image.tag: image tags must be pinned"},
+		},
+		{
+			name:   "deny equals false and zero",
+			policy: "rules:\n  - deny: {path: debug, equals: false}\n  - deny: {path: port, equals: 0}\n  - deny: {path: replicaCount, equals: 0}\n",
+			want: []string{
+				"DENY p.yaml: rule 1: debug: value false is not allowed",
+				"DENY p.yaml: rule 2: port: value 0 is not allowed",
+			},
+		},
+		{
+			name:   "deny matches with a wildcard",
+			policy: "rules:\n  - name: no-root\n    deny: {path: \"containers.*.user\", matches: \"^(0|root)$\"}\n",
+			want:   []string{`DENY p.yaml: no-root: containers[0].user: value "root" is not allowed`},
+		},
+		{
+			name:   "deny any value skips null and missing",
+			policy: "rules:\n  - deny: {path: resources}\n  - deny: {path: missing}\n  - deny: {path: replicaCount}\n",
+			want:   []string{"DENY p.yaml: rule 3: replicaCount: value 1 is not allowed"},
+		},
+		{
+			name:   "require",
+			policy: "rules:\n  - name: limits\n    severity: warn\n    require: {path: resources.limits}\n  - require: {path: \"containers.*.name\"}\n",
+			want:   []string{"WARN p.yaml: limits: resources.limits: required"},
+		},
+		{
+			name:   "cel rule",
+			policy: "rules:\n  - name: ha\n    rule: \"values.replicaCount >= 2\"\n  - rule: \"values.image.tag.startsWith('lat')\"\n",
+			want:   []string{"DENY p.yaml: ha: failed rule: values.replicaCount >= 2"},
+		},
+		{
+			name:   "cel rule over a missing key does not apply",
+			policy: "rules:\n  - rule: \"values.nope == 1\"\n  - rule: \"values.image.nope.startsWith('x')\"\n",
+		},
+		{
+			name:   "cel rule that fails to evaluate",
+			policy: "rules:\n  - rule: \"int(values.image.tag) == 1\"\n",
+			want:   []string{`DENY p.yaml: rule 1: rule "int(values.image.tag) == 1": type conversion error from 'string' to 'int'`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "p.yaml"), []byte(tt.policy), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			files, err := Load(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			findings, err := Evaluate(files, []byte(values))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, f := range findings {
+				got = append(got, f.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findings:\n%q\nwant:\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		rules   int
+		wantErr string
+	}{
+		{
+			name:  "yaml and json files, others ignored",
+			files: map[string]string{"a.yaml": "rules:\n  - require: {path: a}\n", "b.json": `{"rules": [{"rule": "true"}]}`, "notes.txt": "x"},
+			rules: 2,
+		},
+		{name: "empty directory", wantErr: "no policy files"},
+		{name: "unknown field", files: map[string]string{"p.yaml": "rules:\n  - require: {path: a, equal: b}\n"}, wantErr: "unknown field"},
+		{name: "unknown deny field", files: map[string]string{"p.yaml": "rules:\n  - deny: {path: a, equal: b}\n"}, wantErr: `unknown field "equal"`},
+		{name: "equals null", files: map[string]string{"p.yaml": "rules:\n  - deny: {path: a, equals: null}\n"}, wantErr: "rule 1: deny.equals cannot be null"},
+		{name: "two checks", files: map[string]string{"p.yaml": "rules:\n  - {deny: {path: a}, rule: \"true\"}\n"}, wantErr: "set exactly one of deny, require or rule"},
+		{name: "no check", files: map[string]string{"p.yaml": "rules:\n  - name: empty\n"}, wantErr: "empty: set exactly one"},
+		{name: "bad severity", files: map[string]string{"p.yaml": "rules:\n  - {severity: error, rule: \"true\"}\n"}, wantErr: `severity must be deny or warn, got "error"`},
+		{name: "bad regexp", files: map[string]string{"p.yaml": "rules:\n  - deny: {path: a, matches: \"(\"}\n"}, wantErr: "error parsing regexp"},
+		{name: "bad CEL", files: map[string]string{"p.yaml": "rules:\n  - rule: \"values.\"\n"}, wantErr: `rule "values.": ERROR`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, src := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			files, err := Load(dir)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			n := 0
+			for _, f := range files {
+				n += len(f.Rules)
+			}
+			if n != tt.rules {
+				t.Errorf("loaded %d rules, want %d", n, tt.rules)
+			}
+		})
+	}
+}
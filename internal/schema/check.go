@@ -0,0 +1,189 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// valueError is a literal schema value that violates the subschema declaring it.
+type valueError struct {
+	Pointer string // JSON pointer of the value, e.g. "/properties/port/default"
+	Value   any
+	Err     error
+}
+
+func (e valueError) Error() string {
+	return fmt.Sprintf("schema value %s at %s is invalid: %v", formatValue(e.Value), e.Pointer, e.Err)
+}
+
+// checkSchemaValues walks a whole schema and fails on the first "default",
+// "const", "enum" member, "examples" entry or "x-example" that violates its
+// own subschema, so loading a schema catches its bad defaults rather than
+// leaving them to break a user's config later.
+func checkSchemaValues(sch any) error {
+	root, _ := sch.(map[string]any)
+	var first error
+	walkSubschemas(sch, "", func(ptr string, m map[string]any) bool {
+		if errs := subschemaValueErrors(root, ptr, m); len(errs) > 0 {
+			first = errs[0]
+			return false
+		}
+		return true
+	})
+	return first
+}
+
+// subschemaValueErrors validates the literal values a subschema declares
+// against the subschema itself. "enum" members are checked without the enum
+// (and "const" without the const), which they would trivially satisfy.
+func subschemaValueErrors(root map[string]any, ptr string, m map[string]any) []valueError {
+	var out []valueError
+	check := func(sub map[string]any, p string, v any) {
+		if err := validateAgainst(root, sub, v); err != nil {
+			out = append(out, valueError{Pointer: p, Value: v, Err: err})
+		}
+	}
+	if def, ok := m["default"]; ok {
+		check(m, ptr+"/default", def)
+	}
+	if ex, ok := m["examples"].([]any); ok {
+		for i, v := range ex {
+			check(m, fmt.Sprintf("%s/examples/%d", ptr, i), v)
+		}
+	}
+	if ex, ok := m["x-example"]; ok {
+		check(m, ptr+"/x-example", ex)
+	}
+	if c, ok := m["const"]; ok {
+		check(without(m, "const"), ptr+"/const", c)
+	}
+	if enum, ok := m["enum"].([]any); ok {
+		sub := without(m, "enum")
+		for i, v := range enum {
+			check(sub, fmt.Sprintf("%s/enum/%d", ptr, i), v)
+		}
+	}
+	return out
+}
+
+// walkSubschemas calls fn for every subschema (objects only) with its JSON
+// pointer, depth-first; returning false stops the walk.
+func walkSubschemas(s any, ptr string, fn func(ptr string, m map[string]any) bool) bool {
+	m, ok := s.(map[string]any)
+	if !ok {
+		return true
+	}
+	if !fn(ptr, m) {
+		return false
+	}
+	for _, kw := range []string{"additionalProperties", "additionalItems", "not", "if", "then", "else", "contains", "propertyNames"} {
+		if !walkSubschemas(m[kw], ptr+"/"+kw, fn) {
+			return false
+		}
+	}
+	for _, kw := range []string{"properties", "patternProperties", "definitions", "$defs"} {
+		children, _ := m[kw].(map[string]any)
+		for _, k := range sortedKeys(children) {
+			if !walkSubschemas(children[k], ptr+"/"+kw+"/"+escapePointer(k), fn) {
+				return false
+			}
+		}
+	}
+	for _, kw := range []string{"allOf", "anyOf", "oneOf"} {
+		arr, _ := m[kw].([]any)
+		for i, sub := range arr {
+			if !walkSubschemas(sub, fmt.Sprintf("%s/%s/%d", ptr, kw, i), fn) {
+				return false
+			}
+		}
+	}
+	switch items := m["items"].(type) {
+	case map[string]any:
+		return walkSubschemas(items, ptr+"/items", fn)
+	case []any:
+		for i, sub := range items {
+			if !walkSubschemas(sub, fmt.Sprintf("%s/items/%d", ptr, i), fn) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// without returns a shallow copy of m lacking key k.
+func without(m map[string]any, k string) map[string]any {
+	out := make(map[string]any, len(m))
+	for kk, v := range m {
+		if kk != k {
+			out[kk] = v
+		}
+	}
+	return out
+}
+
+// validateAgainst validates v against the subschema sub. Local "$ref"s into
+// "definitions"/"$defs" are resolved against root.
+func validateAgainst(root, sub map[string]any, v any) error {
+	doc := make(map[string]any, len(sub)+2)
+	for k, val := range sub {
+		doc[k] = val
+	}
+	for _, k := range []string{"definitions", "$defs"} {
+		if _, ok := doc[k]; !ok && root[k] != nil {
+			doc[k] = root[k]
+		}
+	}
+
+	res, err := gojsonschema.Validate(gojsonschema.NewGoLoader(doc), gojsonschema.NewGoLoader(v))
+	if err != nil {
+		return fmt.Errorf("jsonschema validate error: %w", err)
+	}
+	if res.Valid() {
+		return nil
+	}
+	msgs := make([]string, 0, len(res.Errors()))
+	for _, e := range res.Errors() {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", e.Field(), e.Description()))
+	}
+	return errors.New(strings.Join(msgs, "; "))
+}
+
+// jsonType returns the JSON Schema type name of a decoded JSON value.
+func jsonType(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if math.Trunc(t) == t {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// typeAllows reports whether a value of JSON type got is accepted by the
+// declared types (an empty list accepts anything).
+func typeAllows(declared []string, got string) bool {
+	if len(declared) == 0 {
+		return true
+	}
+	for _, t := range declared {
+		if t == got || (t == "number" && got == "integer") {
+			return true
+		}
+	}
+	return false
+}
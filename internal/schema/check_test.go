@@ -0,0 +1,91 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckSchemaValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		wantErr string
+	}{
+		{
+			name:   "valid values",
+			schema: "properties:\n  port: {type: integer, default: 80, examples: [8080], enum: [80, 8080]}\n  mode: {const: a, type: string}\n",
+		},
+		{
+			name:    "default of the wrong type",
+			schema:  "properties:\n  port: {type: integer, default: http}\n",
+			wantErr: `schema value "http" at /properties/port/default is invalid`,
+		},
+		{
+			name:    "enum member violating the pattern",
+			schema:  "properties:\n  env: {type: string, pattern: \"^[a-z]+$\", enum: [dev, Prod]}\n",
+			wantErr: `schema value "Prod" at /properties/env/enum/1 is invalid`,
+		},
+		{
+			name:    "const outside its bounds",
+			schema:  "properties:\n  count: {type: integer, minimum: 1, const: 0}\n",
+			wantErr: "schema value 0 at /properties/count/const is invalid",
+		},
+		{
+			name:    "example and x-example",
+			schema:  "properties:\n  tag: {type: string, minLength: 2, x-example: v}\n",
+			wantErr: `schema value "v" at /properties/tag/x-example is invalid`,
+		},
+		{
+			name:    "nested in items and definitions",
+			schema:  "definitions:\n  port: {type: integer, maximum: 65535}\nproperties:\n  ports:\n    type: array\n    items: {$ref: \"#/definitions/port\", default: 70000}\n",
+			wantErr: "schema value 70000 at /properties/ports/items/default is invalid",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkSchemaValues(yamlSchema(t, tt.schema))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// A bad default fails every command that loads the schema, not only the ones
+// that validate a config.
+func TestSchemaValuesCheckedOnLoad(t *testing.T) {
+	dir := t.TempDir()
+	schemaPath := filepath.Join(dir, "config.schema.yaml")
+	cfgPath := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(schemaPath, []byte("type: object\nproperties:\n  port: {type: integer, default: http}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cfgPath, []byte("port: 80\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	const want = "at /properties/port/default is invalid"
+	if _, err := BuildSampleFromSchema(schemaPath, SampleOptions{}); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("gen-sample: err = %v, want %q", err, want)
+	}
+	if _, _, err := PrepareConfig(schemaPath, cfgPath, ConfigOptions{ApplyDefaults: true}); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("PrepareConfig without validation: err = %v, want %q", err, want)
+	}
+	if _, err := RenderDocs(schemaPath, "markdown"); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("docs: err = %v, want %q", err, want)
+	}
+	if _, _, err := PrepareConfig(schemaPath, cfgPath, ConfigOptions{Validate: true}); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("PrepareConfig with validation: err = %v, want %q", err, want)
+	}
+	if err := ValidateYAMLWithSchema(schemaPath, cfgPath); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("ValidateYAMLWithSchema: err = %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,342 @@
+package schema
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// GenerateGo renders Go types for a config schema: a struct per object with
+// json/yaml tags (pointers for optional fields), string enums as named types
+// with constants, and a Default method per struct that fills unset fields
+// from the schema defaults (creating nested objects, like defaults applied by patch).
+func GenerateGo(schemaPath, pkg, typeName string) ([]byte, error) {
+	sch, err := loadSchema(schemaPath)
+	if err != nil {
+		return nil, err
+	}
+	root, _ := sch.(map[string]any)
+	if root == nil {
+		return nil, fmt.Errorf("schema root must be an object")
+	}
+	tm := newTypeModel(root)
+	tm.build(root, typeName)
+
+	g := &goGen{}
+	fmt.Fprintf(&g.buf, "// Code generated by valuesctl codegen go from %s; DO NOT EDIT.\n\n", filepath.Base(schemaPath))
+	fmt.Fprintf(&g.buf, "package %s\n", pkg)
+	for _, t := range tm.sortedNamed() {
+		if t.Kind == "object" {
+			g.emitStruct(t)
+		} else {
+			g.emitEnum(t)
+		}
+	}
+	for _, t := range tm.sortedNamed() {
+		if t.Kind == "object" && hasDefaults(t, map[*typeNode]bool{}) {
+			g.emitDefault(t)
+		}
+	}
+	if g.usesPtr {
+		g.buf.WriteString("\nfunc ptr[T any](v T) *T { return &v }\n")
+	}
+
+	out, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w", err)
+	}
+	return out, nil
+}
+
+type goGen struct {
+	buf     bytes.Buffer
+	usesPtr bool
+}
+
+func (g *goGen) emitStruct(t *typeNode) {
+	g.buf.WriteString("\n")
+	writeGoDoc(&g.buf, "", t.Name, t.Description)
+	fmt.Fprintf(&g.buf, "type %s struct {\n", t.Name)
+	for i, f := range t.Fields {
+		name := goFieldNames(t)[i]
+		writeGoDoc(&g.buf, "\t", name, f.Type.Description)
+		tag := f.Key
+		if !f.Required {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(&g.buf, "\t%s %s `json:%q yaml:%q`\n", name, goFieldType(f), tag, tag)
+	}
+	g.buf.WriteString("}\n")
+}
+
+func (g *goGen) emitEnum(t *typeNode) {
+	g.buf.WriteString("\n")
+	writeGoDoc(&g.buf, "", t.Name, t.Description)
+	fmt.Fprintf(&g.buf, "type %s string\n\nconst (\n", t.Name)
+	seen := map[string]bool{}
+	for i, v := range t.Enum {
+		s := fmt.Sprintf("%v", v)
+		name := t.Name + pascalName(s)
+		if seen[name] || strings.Trim(s, "-_. ") == "" {
+			name = fmt.Sprintf("%s%d", t.Name, i)
+		}
+		seen[name] = true
+		fmt.Fprintf(&g.buf, "\t%s %s = %s\n", name, t.Name, strconv.Quote(s))
+	}
+	g.buf.WriteString(")\n")
+}
+
+func (g *goGen) emitDefault(t *typeNode) {
+	fmt.Fprintf(&g.buf, "\n// Default fills unset fields of %s with their schema defaults.\n", t.Name)
+	fmt.Fprintf(&g.buf, "func (c *%s) Default() {\n", t.Name)
+	for i, f := range t.Fields {
+		name := "c." + goFieldNames(t)[i]
+		ft := f.Type
+		pointer := strings.HasPrefix(goFieldType(f), "*")
+
+		if ft.HasDefault && ft.Default != nil {
+			switch {
+			case pointer:
+				fmt.Fprintf(&g.buf, "\tif %s == nil {\n\t\t%s = %s\n\t}\n", name, name, g.literal(ft, ft.Default, true))
+			case ft.Kind == "array" || ft.Kind == "map" || ft.Kind == "any":
+				fmt.Fprintf(&g.buf, "\tif %s == nil {\n\t\t%s = %s\n\t}\n", name, name, g.literal(ft, ft.Default, false))
+			case ft.Kind == "string" || ft.Kind == "integer" || ft.Kind == "number":
+				// required values: the zero value stands for "unset"
+				zero := map[string]string{"string": `""`, "integer": "0", "number": "0"}[ft.Kind]
+				if lit := g.literal(ft, ft.Default, false); lit != zero && !strings.HasSuffix(lit, `("")`) {
+					fmt.Fprintf(&g.buf, "\tif %s == %s {\n\t\t%s = %s\n\t}\n", name, zero, name, lit)
+				}
+			}
+		}
+
+		switch {
+		case ft.Kind == "object" && hasDefaults(ft, map[*typeNode]bool{}):
+			switch {
+			case pointer && reaches(ft, t, map[*typeNode]bool{}):
+				// a recursive definition: like patch, only fill the levels
+				// the config has, or Default would never return
+				fmt.Fprintf(&g.buf, "\tif %s != nil {\n\t\t%s.Default()\n\t}\n", name, name)
+			case pointer:
+				fmt.Fprintf(&g.buf, "\tif %s == nil {\n\t\t%s = &%s{}\n\t}\n\t%s.Default()\n", name, name, ft.Name, name)
+			default:
+				fmt.Fprintf(&g.buf, "\t%s.Default()\n", name)
+			}
+		case ft.Kind == "array" && ft.Elem.Kind == "object" && hasDefaults(ft.Elem, map[*typeNode]bool{}):
+			fmt.Fprintf(&g.buf, "\tfor i := range %s {\n\t\t%s[i].Default()\n\t}\n", name, name)
+		case ft.Kind == "map" && ft.Elem.Kind == "object" && hasDefaults(ft.Elem, map[*typeNode]bool{}):
+			fmt.Fprintf(&g.buf, "\tfor k, v := range %s {\n\t\tv.Default()\n\t\t%s[k] = v\n\t}\n", name, name)
+		}
+	}
+	g.buf.WriteString("}\n")
+}
+
+// literal renders v as a Go expression of type t (a pointer to it if ptr).
+func (g *goGen) literal(t *typeNode, v any, ptr bool) string {
+	var lit string
+	switch t.Kind {
+	case "object":
+		m, _ := v.(map[string]any)
+		var parts []string
+		for i, f := range t.Fields {
+			fv, ok := m[f.Key]
+			if !ok || fv == nil {
+				continue
+			}
+			parts = append(parts, fmt.Sprintf("%s: %s", goFieldNames(t)[i], g.literal(f.Type, fv, strings.HasPrefix(goFieldType(f), "*"))))
+		}
+		lit = t.Name + "{" + strings.Join(parts, ", ") + "}"
+		if ptr {
+			return "&" + lit
+		}
+		return lit
+	case "array":
+		arr, _ := v.([]any)
+		parts := make([]string, len(arr))
+		for i, e := range arr {
+			parts[i] = g.literal(t.Elem, e, false)
+		}
+		return goType(t) + "{" + strings.Join(parts, ", ") + "}"
+	case "map":
+		m, _ := v.(map[string]any)
+		var parts []string
+		for _, k := range sortedKeys(m) {
+			parts = append(parts, fmt.Sprintf("%q: %s", k, g.literal(t.Elem, m[k], false)))
+		}
+		return goType(t) + "{" + strings.Join(parts, ", ") + "}"
+	case "string":
+		lit = strconv.Quote(fmt.Sprintf("%v", v))
+		if t.Name != "" {
+			lit = t.Name + "(" + lit + ")"
+		}
+	case "integer":
+		f, _ := v.(float64)
+		lit = strconv.FormatInt(int64(f), 10)
+	case "number":
+		f, _ := v.(float64)
+		lit = "float64(" + strconv.FormatFloat(f, 'g', -1, 64) + ")"
+		if !ptr {
+			lit = strconv.FormatFloat(f, 'g', -1, 64)
+		}
+	case "boolean":
+		b, _ := v.(bool)
+		lit = strconv.FormatBool(b)
+	default:
+		return goAnyLiteral(v)
+	}
+	if ptr {
+		g.usesPtr = true
+		return "ptr(" + lit + ")"
+	}
+	return lit
+}
+
+func goAnyLiteral(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return "nil"
+	case string:
+		return strconv.Quote(t)
+	case bool:
+		return strconv.FormatBool(t)
+	case float64:
+		return "float64(" + strconv.FormatFloat(t, 'g', -1, 64) + ")"
+	case []any:
+		parts := make([]string, len(t))
+		for i, e := range t {
+			parts[i] = goAnyLiteral(e)
+		}
+		return "[]any{" + strings.Join(parts, ", ") + "}"
+	case map[string]any:
+		var parts []string
+		for _, k := range sortedKeys(t) {
+			parts = append(parts, fmt.Sprintf("%q: %s", k, goAnyLiteral(t[k])))
+		}
+		return "map[string]any{" + strings.Join(parts, ", ") + "}"
+	}
+	return "nil"
+}
+
+func goType(t *typeNode) string {
+	switch t.Kind {
+	case "object":
+		return t.Name
+	case "map":
+		return "map[string]" + goType(t.Elem)
+	case "array":
+		return "[]" + goType(t.Elem)
+	case "string":
+		if t.Name != "" {
+			return t.Name
+		}
+		return "string"
+	case "integer":
+		return "int"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	}
+	return "any"
+}
+
+// goFieldType is the field's Go type: optional (or nullable) structs and
+// scalars are pointers; slices, maps and any already have a nil value. A
+// required boolean defaulting to true is a pointer too, as its zero value is
+// one the config may set.
+func goFieldType(f typeField) string {
+	base := goType(f.Type)
+	switch f.Type.Kind {
+	case "object", "string", "integer", "number", "boolean":
+		if !f.Required || f.Type.Nullable {
+			return "*" + base
+		}
+	}
+	if f.Type.Kind == "boolean" && f.Type.HasDefault && f.Type.Default == true {
+		return "*" + base
+	}
+	return base
+}
+
+// goFieldNames returns unique Go field names for an object's properties.
+func goFieldNames(t *typeNode) []string {
+	names := make([]string, len(t.Fields))
+	seen := map[string]bool{}
+	for i, f := range t.Fields {
+		n := pascalName(f.Key)
+		for j := 2; seen[n]; j++ {
+			n = fmt.Sprintf("%s%d", pascalName(f.Key), j)
+		}
+		seen[n] = true
+		names[i] = n
+	}
+	return names
+}
+
+// reaches reports whether object type from is target or nests it through
+// object fields, i.e. creating from while filling target's defaults recurses.
+func reaches(from, target *typeNode, seen map[*typeNode]bool) bool {
+	if from.Kind != "object" || seen[from] {
+		return false
+	}
+	if from.Name == target.Name { // a $ref's type may be a copy of the named one
+		return true
+	}
+	seen[from] = true
+	for _, f := range from.Fields {
+		if reaches(f.Type, target, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasDefaults reports whether filling t's defaults would set anything.
+func hasDefaults(t *typeNode, seen map[*typeNode]bool) bool {
+	if t.Kind != "object" || seen[t] {
+		return false
+	}
+	seen[t] = true
+	for _, f := range t.Fields {
+		if f.Type.HasDefault && f.Type.Default != nil {
+			return true
+		}
+		if hasDefaults(f.Type, seen) || (f.Type.Elem != nil && hasDefaults(f.Type.Elem, seen)) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeGoDoc writes a description as a Go comment. Type comments are a
+// sentence starting with the type name ("Config is the app config.") unless
+// the description already starts with it; field comments are the description
+// as-is.
+func writeGoDoc(b *bytes.Buffer, indent, name, desc string) {
+	if desc == "" {
+		return
+	}
+	if indent == "" && !strings.HasPrefix(desc, name+" ") {
+		desc = name + " is " + lowerFirst(desc)
+		if !strings.ContainsAny(desc[len(desc)-1:], ".!?") {
+			desc += "."
+		}
+	}
+	for _, l := range strings.Split(desc, "\n") {
+		fmt.Fprintf(b, "%s// %s\n", indent, strings.TrimSpace(l))
+	}
+}
+
+// lowerFirst lowercases the first letter of s unless it starts an acronym
+// ("API gateway" stays as is).
+func lowerFirst(s string) string {
+	r := []rune(s)
+	if len(r) == 0 || (len(r) > 1 && unicode.IsUpper(r[1])) {
+		return s
+	}
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
@@ -0,0 +1,131 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateGo(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		want   []string // snippets of the generated source
+		absent []string
+	}{
+		{
+			name:   "required and optional fields",
+			schema: "type: object\nrequired: [name]\nproperties:\n  name: {type: string}\n  replicas: {type: integer}\n  ratio: {type: number}\n  debug: {type: boolean}\n",
+			want: []string{
+				"type Config struct {",
+				"Name     string   `json:\"name\" yaml:\"name\"`",
+				"Replicas *int     `json:\"replicas,omitempty\" yaml:\"replicas,omitempty\"`",
+				"Ratio    *float64 `json:\"ratio,omitempty\" yaml:\"ratio,omitempty\"`",
+				"Debug    *bool    `json:\"debug,omitempty\" yaml:\"debug,omitempty\"`",
+			},
+		},
+		{
+			name:   "type list uses the first declared type",
+			schema: "type: object\nrequired: [port, count]\nproperties:\n  port: {type: [string, integer]}\n  count: {type: [\"null\", integer, string]}\n",
+			want:   []string{"Port  string", "Count *int"},
+		},
+		{
+			name:   "doc comments",
+			schema: "description: Settings for the app\ntype: object\nproperties:\n  image:\n    description: Config image settings.\n    type: object\n    properties:\n      tag: {type: string, description: Image tag}\n  env: {type: string, enum: [dev, prod], description: API environment}\n",
+			want: []string{
+				"// Config is settings for the app.\ntype Config struct",
+				"// ConfigImage is config image settings.\ntype ConfigImage struct",
+				"// ConfigEnv is API environment.\ntype ConfigEnv string",
+				"\t// Image tag\n\tTag *string",
+			},
+			absent: []string{"// Config: "},
+		},
+		{
+			name:   "description starting with the type name",
+			schema: "description: Config holds everything.\ntype: object\nproperties:\n  a: {type: string}\n",
+			want:   []string{"// Config holds everything.\ntype Config struct"},
+		},
+		{
+			name:   "enum constants and defaults",
+			schema: "type: object\nproperties:\n  env: {type: string, enum: [dev, prod], default: dev}\n  image:\n    type: object\n    properties:\n      tag: {type: string, default: latest}\n",
+			want: []string{
+				"ConfigEnvDev  ConfigEnv = \"dev\"",
+				"ConfigEnvProd ConfigEnv = \"prod\"",
+				"if c.Env == nil {\n\t\tc.Env = ptr(ConfigEnv(\"dev\"))",
+				"if c.Image == nil {\n\t\tc.Image = &ConfigImage{}\n\t}\n\tc.Image.Default()",
+				"func ptr[T any](v T) *T { return &v }",
+			},
+		},
+		{
+			name:   "recursive ref with a default",
+			schema: "definitions:\n  node:\n    type: object\n    properties:\n      name: {type: string, default: leaf}\n      left: {$ref: \"#/definitions/node\"}\ntype: object\nproperties:\n  tree: {$ref: \"#/definitions/node\"}\n",
+			want: []string{
+				"if c.Tree == nil {\n\t\tc.Tree = &Node{}\n\t}\n\tc.Tree.Default()",
+				"if c.Left != nil {\n\t\tc.Left.Default()\n\t}",
+			},
+			absent: []string{"c.Left = &Node{}"},
+		},
+		{
+			name:   "required booleans with defaults",
+			schema: "type: object\nrequired: [enabled, debug]\nproperties:\n  enabled: {type: boolean, default: true}\n  debug: {type: boolean, default: false}\n",
+			want: []string{
+				"Debug   bool  `json:\"debug\" yaml:\"debug\"`",
+				"Enabled *bool `json:\"enabled\" yaml:\"enabled\"`",
+				"if c.Enabled == nil {\n\t\tc.Enabled = ptr(true)",
+			},
+		},
+		{
+			name:   "maps and refs",
+			schema: "type: object\ndefinitions:\n  res:\n    type: object\n    properties:\n      cpu: {type: string}\nproperties:\n  labels: {type: object, additionalProperties: {type: string}}\n  limits: {$ref: \"#/definitions/res\"}\n  requests: {$ref: \"#/definitions/res\"}\n",
+			want: []string{
+				"Labels   map[string]string",
+				"Limits   *Res",
+				"Requests *Res",
+				"type Res struct",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := filepath.Join(t.TempDir(), "config.schema.yaml")
+			if err := os.WriteFile(p, []byte(tt.schema), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			out, err := GenerateGo(p, "config", "Config")
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(string(out), w) {
+					t.Errorf("missing %q in:\n%s", w, out)
+				}
+			}
+			for _, a := range tt.absent {
+				if strings.Contains(string(out), a) {
+					t.Errorf("unexpected %q in:\n%s", a, out)
+				}
+			}
+		})
+	}
+}
+
+func TestSchemaKind(t *testing.T) {
+	tests := []struct {
+		schema map[string]any
+		want   string
+	}{
+		{map[string]any{"type": "string"}, "string"},
+		{map[string]any{"type": []any{"string", "integer"}}, "string"},
+		{map[string]any{"type": []any{"null", "integer"}}, "integer"},
+		{map[string]any{"type": []any{"null"}}, "null"},
+		{map[string]any{"properties": map[string]any{}}, "object"},
+		{map[string]any{"items": map[string]any{}}, "array"},
+		{map[string]any{}, ""},
+	}
+	for _, tt := range tests {
+		if got := schemaKind(tt.schema); got != tt.want {
+			t.Errorf("schemaKind(%v) = %q, want %q", tt.schema, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// GenerateTS renders TypeScript declarations for a config schema: an
+// interface per object (optional properties marked with ?), string enums as
+// string-literal union types, and descriptions/defaults as JSDoc comments.
+func GenerateTS(schemaPath, typeName string) ([]byte, error) {
+	sch, err := loadSchema(schemaPath)
+	if err != nil {
+		return nil, err
+	}
+	root, _ := sch.(map[string]any)
+	if root == nil {
+		return nil, fmt.Errorf("schema root must be an object")
+	}
+	tm := newTypeModel(root)
+	tm.build(root, typeName)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by valuesctl codegen ts from %s; DO NOT EDIT.\n", filepath.Base(schemaPath))
+	for _, t := range tm.sortedNamed() {
+		b.WriteString("\n")
+		writeJSDoc(&b, "", t.Description, nil, false)
+		if t.Kind != "object" {
+			fmt.Fprintf(&b, "export type %s = %s;\n", t.Name, tsUnion(t.Enum))
+			continue
+		}
+		fmt.Fprintf(&b, "export interface %s {\n", t.Name)
+		for _, f := range t.Fields {
+			writeJSDoc(&b, "  ", f.Type.Description, f.Type.Default, f.Type.HasDefault)
+			opt := ""
+			if !f.Required {
+				opt = "?"
+			}
+			fmt.Fprintf(&b, "  %s%s: %s;\n", tsKey(f.Key), opt, tsType(f.Type))
+		}
+		b.WriteString("}\n")
+	}
+	return b.Bytes(), nil
+}
+
+func tsType(t *typeNode) string {
+	var out string
+	switch t.Kind {
+	case "object":
+		out = t.Name
+	case "map":
+		out = "Record<string, " + tsType(t.Elem) + ">"
+	case "array":
+		elem := tsType(t.Elem)
+		if strings.Contains(elem, " | ") {
+			elem = "(" + elem + ")"
+		}
+		out = elem + "[]"
+	case "string", "integer", "number", "boolean":
+		switch {
+		case t.Name != "":
+			out = t.Name
+		case len(t.Enum) > 0:
+			out = tsUnion(t.Enum)
+		case t.Kind == "boolean":
+			out = "boolean"
+		case t.Kind == "string":
+			out = "string"
+		default:
+			out = "number"
+		}
+	default:
+		return "unknown"
+	}
+	if t.Nullable {
+		out += " | null"
+	}
+	return out
+}
+
+// tsUnion renders enum values as a union of literal types.
+func tsUnion(enum []any) string {
+	parts := make([]string, 0, len(enum))
+	for _, v := range enum {
+		parts = append(parts, tsLiteral(v))
+	}
+	return strings.Join(parts, " | ")
+}
+
+func tsLiteral(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "unknown"
+	}
+	return string(b)
+}
+
+var tsIdent = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// tsKey quotes property names that are not valid identifiers.
+func tsKey(k string) string {
+	if tsIdent.MatchString(k) {
+		return k
+	}
+	return tsLiteral(k)
+}
+
+// writeJSDoc writes a /** */ comment with the description and, if present,
+// an @default tag.
+func writeJSDoc(b *bytes.Buffer, indent, desc string, def any, hasDefault bool) {
+	var lines []string
+	if desc != "" {
+		lines = strings.Split(desc, "\n")
+	}
+	if hasDefault {
+		lines = append(lines, "@default "+tsLiteral(def))
+	}
+	if len(lines) == 0 {
+		return
+	}
+	if len(lines) == 1 {
+		fmt.Fprintf(b, "%s/** %s */\n", indent, jsdocEscape(lines[0]))
+		return
+	}
+	fmt.Fprintf(b, "%s/**\n", indent)
+	for _, l := range lines {
+		fmt.Fprintf(b, "%s * %s\n", indent, jsdocEscape(strings.TrimSpace(l)))
+	}
+	fmt.Fprintf(b, "%s */\n", indent)
+}
+
+func jsdocEscape(s string) string {
+	return strings.ReplaceAll(s, "*/", "*\\/")
+}
@@ -0,0 +1,82 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateTS(t *testing.T) {
+	const header = "// Code generated by valuesctl codegen ts from config.schema.yaml; DO NOT EDIT.\n"
+	tests := []struct {
+		name   string
+		schema string
+		want   string
+	}{
+		{
+			name:   "required, optional and nullable properties",
+			schema: "type: object\nrequired: [name]\nproperties:\n  name: {type: string}\n  replicas: {type: [integer, \"null\"]}\n  hosts: {type: array, items: {type: string}}\n  \"app-id\": {type: string}\n",
+			want: header + `
+export interface Config {
+  "app-id"?: string;
+  hosts?: string[];
+  name: string;
+  replicas?: number | null;
+}
+`,
+		},
+		{
+			name:   "enums, maps and nested objects with JSDoc",
+			schema: "description: Settings for the app.\ntype: object\nproperties:\n  env: {type: string, enum: [dev, prod], default: dev, description: Environment}\n  level: {type: integer, enum: [1, 2]}\n  labels: {type: object, additionalProperties: {type: string}}\n  image:\n    type: object\n    description: \"Image, see */docs\"\n    properties:\n      tag: {type: string, default: latest}\n",
+			want: header + `
+/** Settings for the app. */
+export interface Config {
+  /**
+   * Environment
+   * @default "dev"
+   */
+  env?: ConfigEnv;
+  /** Image, see *\/docs */
+  image?: ConfigImage;
+  labels?: Record<string, string>;
+  level?: 1 | 2;
+}
+
+/** Environment */
+export type ConfigEnv = "dev" | "prod";
+
+/** Image, see *\/docs */
+export interface ConfigImage {
+  /** @default "latest" */
+  tag?: string;
+}
+`,
+		},
+		{
+			name:   "untyped values and unions",
+			schema: "type: object\nproperties:\n  extra: {}\n  port: {oneOf: [{type: integer}, {type: string}]}\n  tags: {type: array, items: {type: [string, \"null\"]}}\n",
+			want: header + `
+export interface Config {
+  extra?: unknown;
+  port?: unknown;
+  tags?: (string | null)[];
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := filepath.Join(t.TempDir(), "config.schema.yaml")
+			if err := os.WriteFile(p, []byte(tt.schema), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			out, err := GenerateTS(p, "Config")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(out) != tt.want {
+				t.Errorf("output:\n%s\nwant:\n%s", out, tt.want)
+			}
+		})
+	}
+}
@@ -4,103 +4,153 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
 
 	y3 "gopkg.in/yaml.v3"
 	"sigs.k8s.io/yaml"
 )
 
+// ConfigOptions controls how PrepareConfig processes a config against its schema.
+type ConfigOptions struct {
+	Validate      bool // validate the (migrated) config against the schema
+	ApplyDefaults bool // fill missing keys from schema "default" values
+}
+
 // LoadConfigWithSchemaDefaults reads schema (YAML/JSON) and config (YAML),
 // and if applyDefaults==true it merges "default" values from the schema into the config.
 // Returns a map[string]any ready for templating.
 func LoadConfigWithSchemaDefaults(schemaPath, cfgPath string, applyDefaults bool) (map[string]any, error) {
+	m, _, err := PrepareConfig(schemaPath, cfgPath, ConfigOptions{ApplyDefaults: applyDefaults})
+	return m, err
+}
+
+// PrepareConfig reads config (YAML) and, when schemaPath is set, migrates keys
+// renamed via "x-renamed-from", optionally validates the result (schema and
+// "x-rules") and applies schema defaults. Deprecated keys found along the way
+// are returned so the caller can decide whether to warn or fail.
+func PrepareConfig(schemaPath, cfgPath string, opts ConfigOptions) (map[string]any, []Deprecation, error) {
 	// Load config YAML -> map
 	cfgBytes, err := os.ReadFile(cfgPath)
 	if err != nil {
-		return nil, fmt.Errorf("read config: %w", err)
+		return nil, nil, fmt.Errorf("read config: %w", err)
 	}
 	var cfg any
 	if err := y3.Unmarshal(cfgBytes, &cfg); err != nil {
-		return nil, fmt.Errorf("unmarshal config yaml: %w", err)
+		return nil, nil, fmt.Errorf("unmarshal config yaml: %w", err)
 	}
 
-	// If not applying defaults, just coerce to map and return.
-	if !applyDefaults || schemaPath == "" {
+	// Without a schema there is nothing to migrate, validate or default.
+	if schemaPath == "" {
 		m, _ := toMapStringAny(cfg)
-		return m, nil
+		return m, nil, nil
 	}
 
-	// Load schema (YAML or JSON) -> JSON -> interface{}
-	sraw, err := os.ReadFile(schemaPath)
+	schemaJSON, err := readSchemaJSON(schemaPath)
 	if err != nil {
-		return nil, fmt.Errorf("read schema: %w", err)
+		return nil, nil, err
 	}
-	schemaJSON := sraw
-	if looksLikeYAML(schemaPath, sraw) {
-		schemaJSON, err = yaml.YAMLToJSON(sraw)
+	sch, err := parseSchema(schemaJSON)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	deprecations := migrateDeprecations(sch, cfg, "")
+
+	if opts.Validate {
+		// Validate the JSON view of the config (YAML timestamps stay strings),
+		// migrated the same way as the templating view.
+		dataJSON, err := yaml.YAMLToJSON(cfgBytes)
 		if err != nil {
-			return nil, fmt.Errorf("schema YAML->JSON: %w", err)
+			return nil, nil, fmt.Errorf("config YAML->JSON: %w", err)
+		}
+		var doc any
+		if err := json.Unmarshal(dataJSON, &doc); err != nil {
+			return nil, nil, fmt.Errorf("config json unmarshal: %w", err)
+		}
+		migrateDeprecations(sch, doc, "")
+		if dataJSON, err = json.Marshal(doc); err != nil {
+			return nil, nil, fmt.Errorf("config json marshal: %w", err)
+		}
+		if err := validateJSON(schemaJSON, dataJSON); err != nil {
+			return nil, nil, fmt.Errorf("config validation failed: %w", err)
+		}
+		// x-rules see the config as templates do, with defaults applied
+		if err := checkRules(sch, applyDefaultsNode(sch, doc)); err != nil {
+			return nil, nil, fmt.Errorf("config validation failed: %w", err)
 		}
-	}
-	var sch any
-	if err := json.Unmarshal(schemaJSON, &sch); err != nil {
-		return nil, fmt.Errorf("schema json unmarshal: %w", err)
 	}
 
 	// Apply defaults recursively (mutates cfg)
-	cfg = applyDefaultsNode(sch, cfg)
+	if opts.ApplyDefaults {
+		cfg = applyDefaultsNode(sch, cfg)
+	}
 
 	// Ensure map[string]any
 	out, _ := toMapStringAny(cfg)
-	return out, nil
+	return out, deprecations, nil
 }
 
+// applyDefaultsNode fills the values missing from cfg with the defaults of
+// schema, following local $refs and allOf branches as checkRules does.
 func applyDefaultsNode(schema any, cfg any) any {
+	root, _ := schema.(map[string]any)
+	d := &defaulter{root: root, active: map[string]bool{}}
+	return d.apply(schema, cfg)
+}
+
+type defaulter struct {
+	root map[string]any
+	// active holds the $refs being expanded, so a recursive definition stops
+	// once there is no config left to fill
+	active map[string]bool
+}
+
+func (d *defaulter) apply(schema any, cfg any) any {
 	sm, ok := schema.(map[string]any)
 	if !ok {
 		return cfg
 	}
+	if ref, ok := sm["$ref"].(string); ok {
+		if cfg == nil && d.active[ref] {
+			return cfg
+		}
+		d.active[ref] = true
+		defer delete(d.active, ref)
+		sm = resolveRef(d.root, sm)
+	}
+	// allOf: fold the branches' properties and defaults into this node
+	sm = mergeAllOf(d.root, sm)
 
 	// If the schema node has a "default" and cfg is nil, use it.
 	if def, ok := sm["default"]; ok && (cfg == nil) {
 		return cloneJSON(def)
 	}
 
-	// allOf: merge subschema defaults into cfg
-	if arr, ok := sm["allOf"].([]any); ok {
-		for _, sub := range arr {
-			cfg = applyDefaultsNode(sub, cfg)
-		}
-	}
-
 	// oneOf/anyOf: we *could* pick the first; for defaults, we won’t guess.
 	// If you want picking-first behavior, uncomment one of these:
-	// if arr, ok := sm["oneOf"].([]any); ok && len(arr) > 0 { cfg = applyDefaultsNode(arr[0], cfg) }
-	// if arr, ok := sm["anyOf"].([]any); ok && len(arr) > 0 { cfg = applyDefaultsNode(arr[0], cfg) }
-
-	switch sm["type"] {
-	case "object":
-		props, _ := sm["properties"].(map[string]any)
-		// If cfg is nil and schema has object default, handled above.
-		// If cfg is nil and no default: start with empty object to receive per-property defaults
-		cm, _ := toMapStringAny(cfg)
-		if cm == nil {
-			cm = map[string]any{}
-		}
-		for name, sub := range props {
-			cur := cm[name]
-			cm[name] = applyDefaultsNode(sub, cur)
-		}
-		return cm
-
-	case "array":
-		// If schema has a default for array and cfg==nil, handled above.
-		// If cfg is an array and items has defaults for elements, we don’t synthesize elements.
-		// (We keep user data intact.)
-		return cfg
+	// if arr, ok := sm["oneOf"].([]any); ok && len(arr) > 0 { cfg = d.apply(arr[0], cfg) }
+	// if arr, ok := sm["anyOf"].([]any); ok && len(arr) > 0 { cfg = d.apply(arr[0], cfg) }
 
-	default:
+	// Only objects receive per-property defaults; with a type list such as
+	// [object, "null"] a value of another listed type is kept as is.
+	if !slices.Contains(declaredTypes(sm), "object") {
+		// arrays: we don’t synthesize elements (we keep user data intact);
 		// primitives: if default exists and cfg==nil it was already set
 		return cfg
 	}
+	props, _ := sm["properties"].(map[string]any)
+	// If cfg is nil and schema has object default, handled above.
+	// If cfg is nil and no default: start with empty object to receive per-property defaults
+	cm, ok := toMapStringAny(cfg)
+	if !ok {
+		if cfg != nil {
+			return cfg
+		}
+		cm = map[string]any{}
+	}
+	for name, sub := range props {
+		cur := cm[name]
+		cm[name] = d.apply(sub, cur)
+	}
+	return cm
 }
-
@@ -0,0 +1,133 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Deprecation reports a config key that the schema marks as deprecated
+// ("deprecated: true") or that was migrated from an old name ("x-renamed-from").
+type Deprecation struct {
+	Path    string // dotted path of the key as it appeared in the config
+	Message string
+}
+
+func (d Deprecation) String() string {
+	return d.Path + ": " + d.Message
+}
+
+// FormatDeprecations renders deprecations as a bullet list, one per line.
+func FormatDeprecations(ds []Deprecation) string {
+	var b strings.Builder
+	for _, d := range ds {
+		fmt.Fprintf(&b, "- %s\n", d)
+	}
+	return b.String()
+}
+
+// migrateDeprecations walks cfg alongside the schema, moving keys named in a
+// property's "x-renamed-from" to the property's name and collecting keys whose
+// schema is marked deprecated. Local $refs and allOf branches are followed.
+// cfg is modified in place.
+func migrateDeprecations(schema any, cfg any, path string) []Deprecation {
+	root, _ := schema.(map[string]any)
+	return (&migrator{root: root}).migrate(schema, cfg, path)
+}
+
+type migrator struct {
+	root map[string]any
+}
+
+// node resolves a schema node's $ref and folds its allOf branches.
+func (mg *migrator) node(schema any) map[string]any {
+	sm, ok := schema.(map[string]any)
+	if !ok {
+		return nil
+	}
+	return mergeAllOf(mg.root, resolveRef(mg.root, sm))
+}
+
+func (mg *migrator) migrate(schema any, cfg any, path string) []Deprecation {
+	sm := mg.node(schema)
+	if sm == nil {
+		return nil
+	}
+
+	var out []Deprecation
+	switch c := cfg.(type) {
+	case map[string]any:
+		props, _ := sm["properties"].(map[string]any)
+		names := sortedKeys(props)
+
+		// Renames first, so deprecation checks and recursion see the new names.
+		for _, name := range names {
+			sub := mg.node(props[name])
+			for _, old := range renamedFrom(sub) {
+				v, ok := c[old]
+				if !ok {
+					continue
+				}
+				msg := fmt.Sprintf("renamed to %q", joinPath(path, name))
+				if _, exists := c[name]; exists {
+					msg += fmt.Sprintf("; value ignored because %q is also set", name)
+				} else {
+					c[name] = v
+				}
+				delete(c, old)
+				out = append(out, Deprecation{Path: joinPath(path, old), Message: msg})
+			}
+		}
+
+		for _, name := range names {
+			v, ok := c[name]
+			if !ok {
+				continue
+			}
+			sub := mg.node(props[name])
+			if dep, _ := sub["deprecated"].(bool); dep {
+				msg, _ := sub["x-deprecated-message"].(string)
+				if strings.TrimSpace(msg) == "" {
+					msg = "deprecated"
+				}
+				out = append(out, Deprecation{Path: joinPath(path, name), Message: msg})
+			}
+			out = append(out, mg.migrate(sub, v, joinPath(path, name))...)
+		}
+
+		// Keys validated by additionalProperties may carry deprecations further down.
+		if aps, ok := sm["additionalProperties"].(map[string]any); ok {
+			for _, k := range sortedKeys(c) {
+				if _, known := props[k]; known {
+					continue
+				}
+				out = append(out, mg.migrate(aps, c[k], joinPath(path, k))...)
+			}
+		}
+
+	case []any:
+		if items, ok := sm["items"].(map[string]any); ok {
+			for i, e := range c {
+				out = append(out, mg.migrate(items, e, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	}
+	return out
+}
+
+// renamedFrom returns the old names declared by "x-renamed-from", which may be
+// a single string or a list of strings.
+func renamedFrom(sub map[string]any) []string {
+	switch v := sub["x-renamed-from"].(type) {
+	case string:
+		return []string{v}
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, e := range v {
+			if s, ok := e.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
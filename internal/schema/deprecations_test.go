@@ -0,0 +1,140 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMigrateDeprecations(t *testing.T) {
+	sch := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"tlsSecretName": map[string]any{"type": "string", "x-renamed-from": "tlsSecret"},
+			"hosts":         map[string]any{"type": "array", "x-renamed-from": []any{"host", "hostnames"}},
+			"legacy":        map[string]any{"type": "string", "deprecated": true, "x-deprecated-message": "use app.footer"},
+			"old":           map[string]any{"type": "string", "deprecated": true},
+			"app": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"name": map[string]any{"type": "string", "x-renamed-from": "appName"},
+				},
+			},
+			"tls": map[string]any{"$ref": "#/definitions/tls"},
+			"extra": map[string]any{
+				"allOf": []any{map[string]any{"properties": map[string]any{"label": map[string]any{"x-renamed-from": "tag"}}}},
+			},
+			"items": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type":       "object",
+					"properties": map[string]any{"id": map[string]any{"x-renamed-from": "key"}},
+				},
+			},
+		},
+		"definitions": map[string]any{
+			"tls": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"secretName": map[string]any{"type": "string", "x-renamed-from": "secret"},
+					"issuer":     map[string]any{"type": "string", "deprecated": true},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name string
+		cfg  map[string]any
+		want map[string]any
+		deps []Deprecation
+	}{
+		{
+			name: "no deprecated keys",
+			cfg:  map[string]any{"tlsSecretName": "s"},
+			want: map[string]any{"tlsSecretName": "s"},
+		},
+		{
+			name: "rename",
+			cfg:  map[string]any{"tlsSecret": "s"},
+			want: map[string]any{"tlsSecretName": "s"},
+			deps: []Deprecation{{Path: "tlsSecret", Message: `renamed to "tlsSecretName"`}},
+		},
+		{
+			name: "rename from list",
+			cfg:  map[string]any{"hostnames": []any{"a"}},
+			want: map[string]any{"hosts": []any{"a"}},
+			deps: []Deprecation{{Path: "hostnames", Message: `renamed to "hosts"`}},
+		},
+		{
+			name: "new name wins",
+			cfg:  map[string]any{"tlsSecret": "old", "tlsSecretName": "new"},
+			want: map[string]any{"tlsSecretName": "new"},
+			deps: []Deprecation{{Path: "tlsSecret", Message: `renamed to "tlsSecretName"; value ignored because "tlsSecretName" is also set`}},
+		},
+		{
+			name: "deprecated with and without message",
+			cfg:  map[string]any{"legacy": "x", "old": "y"},
+			want: map[string]any{"legacy": "x", "old": "y"},
+			deps: []Deprecation{{Path: "legacy", Message: "use app.footer"}, {Path: "old", Message: "deprecated"}},
+		},
+		{
+			name: "nested and array items",
+			cfg:  map[string]any{"app": map[string]any{"appName": "a"}, "items": []any{map[string]any{"key": 1}}},
+			want: map[string]any{"app": map[string]any{"name": "a"}, "items": []any{map[string]any{"id": 1}}},
+			deps: []Deprecation{{Path: "app.appName", Message: `renamed to "app.name"`}, {Path: "items[0].key", Message: `renamed to "items[0].id"`}},
+		},
+		{
+			name: "through $ref and allOf",
+			cfg:  map[string]any{"tls": map[string]any{"secret": "s", "issuer": "x"}, "extra": map[string]any{"tag": "t"}},
+			want: map[string]any{"tls": map[string]any{"secretName": "s", "issuer": "x"}, "extra": map[string]any{"label": "t"}},
+			deps: []Deprecation{{Path: "extra.tag", Message: `renamed to "extra.label"`}, {Path: "tls.secret", Message: `renamed to "tls.secretName"`}, {Path: "tls.issuer", Message: "deprecated"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deps := migrateDeprecations(sch, tt.cfg, "")
+			if !reflect.DeepEqual(tt.cfg, tt.want) {
+				t.Errorf("config = %v, want %v", tt.cfg, tt.want)
+			}
+			if !reflect.DeepEqual(deps, tt.deps) {
+				t.Errorf("deprecations = %v, want %v", deps, tt.deps)
+			}
+		})
+	}
+}
+
+// A key renamed inside a $ref target is migrated before validation, so
+// additionalProperties: false does not reject the old name.
+func TestPrepareConfigRenamedThroughRef(t *testing.T) {
+	const schema = `type: object
+properties:
+  tls: {$ref: "#/definitions/tls"}
+definitions:
+  tls:
+    type: object
+    additionalProperties: false
+    properties:
+      secretName: {type: string, x-renamed-from: secret}
+`
+	dir := t.TempDir()
+	schemaPath := filepath.Join(dir, "config.schema.yaml")
+	cfgPath := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(schemaPath, []byte(schema), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cfgPath, []byte("tls:\n  secret: s\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, deps, err := PrepareConfig(schemaPath, cfgPath, ConfigOptions{Validate: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]any{"tls": map[string]any{"secretName": "s"}}; !reflect.DeepEqual(cfg, want) {
+		t.Errorf("config = %v, want %v", cfg, want)
+	}
+	if want := []Deprecation{{Path: "tls.secret", Message: `renamed to "tls.secretName"`}}; !reflect.DeepEqual(deps, want) {
+		t.Errorf("deprecations = %v, want %v", deps, want)
+	}
+}
@@ -0,0 +1,326 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Change is a single difference between two versions of a schema.
+type Change struct {
+	Path     string // config path; "[]" marks array items, ".*" additionalProperties, "/anyOf[1]" a branch
+	Detail   string
+	Breaking bool
+}
+
+func (c Change) String() string {
+	path := c.Path
+	if path == "" {
+		path = "(root)"
+	}
+	return path + ": " + c.Detail
+}
+
+// DiffSchemas compares two schemas (YAML or JSON) and classifies each change as
+// breaking (an existing valid config may become invalid) or non-breaking.
+// Local $refs are followed and allOf/oneOf/anyOf branches are compared by
+// position. The schemas are compared as written: their defaults and examples
+// are not checked.
+func DiffSchemas(oldPath, newPath string) ([]Change, error) {
+	oldSch, err := decodeSchema(oldPath)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", oldPath, err)
+	}
+	newSch, err := decodeSchema(newPath)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", newPath, err)
+	}
+	return diffSchemas(oldSch, newSch), nil
+}
+
+func diffSchemas(oldSch, newSch any) []Change {
+	oldMap, _ := oldSch.(map[string]any)
+	newMap, _ := newSch.(map[string]any)
+	d := &differ{oldRoot: oldMap, newRoot: newMap, active: map[string]bool{}}
+	changes := d.diffNode("", oldMap, newMap)
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+// differ compares two schema trees, resolving $refs in each against its own
+// root. active holds the ref pairs being compared, so recursive definitions
+// are walked once.
+type differ struct {
+	oldRoot, newRoot map[string]any
+	active           map[string]bool
+}
+
+func (d *differ) diffNode(path string, o, n map[string]any) []Change {
+	oref, _ := o["$ref"].(string)
+	nref, _ := n["$ref"].(string)
+	if oref != "" || nref != "" {
+		key := oref + "\x00" + nref
+		if d.active[key] {
+			return nil
+		}
+		d.active[key] = true
+		defer delete(d.active, key)
+	}
+	o, n = resolveRef(d.oldRoot, o), resolveRef(d.newRoot, n)
+
+	var out []Change
+	add := func(breaking bool, format string, args ...any) {
+		out = append(out, Change{Path: path, Detail: fmt.Sprintf(format, args...), Breaking: breaking})
+	}
+
+	// type
+	ot, nt := schemaTypes(o), schemaTypes(n)
+	switch {
+	case len(ot) == 0 && len(nt) > 0:
+		add(true, "type restricted to %s", strings.Join(nt, "|"))
+	case len(ot) > 0 && len(nt) == 0:
+		add(false, "type restriction %s removed", strings.Join(ot, "|"))
+	case !reflect.DeepEqual(ot, nt):
+		if typesCovered(ot, nt) {
+			add(false, "type widened from %s to %s", strings.Join(ot, "|"), strings.Join(nt, "|"))
+		} else {
+			add(true, "type changed from %s to %s", strings.Join(ot, "|"), strings.Join(nt, "|"))
+		}
+	}
+
+	// required
+	oreq, nreq := stringSet(o["required"]), stringSet(n["required"])
+	for _, k := range sortedSet(nreq) {
+		if !oreq[k] {
+			out = append(out, Change{Path: joinPath(path, k), Detail: "now required", Breaking: true})
+		}
+	}
+	for _, k := range sortedSet(oreq) {
+		if !nreq[k] {
+			out = append(out, Change{Path: joinPath(path, k), Detail: "no longer required"})
+		}
+	}
+
+	// enum / const
+	oenum, oHasEnum := o["enum"].([]any)
+	nenum, nHasEnum := n["enum"].([]any)
+	switch {
+	case !oHasEnum && nHasEnum:
+		add(true, "enum introduced: %s", formatValue(nenum))
+	case oHasEnum && !nHasEnum:
+		add(false, "enum removed")
+	case oHasEnum && nHasEnum:
+		if removed := missingValues(oenum, nenum); len(removed) > 0 {
+			add(true, "enum narrowed: removed %s", formatValue(removed))
+		}
+		if added := missingValues(nenum, oenum); len(added) > 0 {
+			add(false, "enum widened: added %s", formatValue(added))
+		}
+	}
+	oc, oHasConst := o["const"]
+	nc, nHasConst := n["const"]
+	if nHasConst && (!oHasConst || !reflect.DeepEqual(oc, nc)) {
+		add(true, "const set to %s", formatValue(nc))
+	} else if oHasConst && !nHasConst {
+		add(false, "const removed")
+	}
+
+	// default
+	od, oHasDef := o["default"]
+	nd, nHasDef := n["default"]
+	switch {
+	case oHasDef && nHasDef && !reflect.DeepEqual(od, nd):
+		add(false, "default changed from %s to %s", formatValue(od), formatValue(nd))
+	case !oHasDef && nHasDef:
+		add(false, "default added: %s", formatValue(nd))
+	case oHasDef && !nHasDef:
+		add(false, "default removed (was %s)", formatValue(od))
+	}
+
+	// numeric/length bounds: a larger lower bound or smaller upper bound is breaking
+	for _, kw := range []string{"minimum", "exclusiveMinimum", "minLength", "minItems", "minProperties"} {
+		out = append(out, diffBound(path, kw, o, n, true)...)
+	}
+	for _, kw := range []string{"maximum", "exclusiveMaximum", "maxLength", "maxItems", "maxProperties"} {
+		out = append(out, diffBound(path, kw, o, n, false)...)
+	}
+	for _, kw := range []string{"pattern", "format"} {
+		ov, oOK := o[kw].(string)
+		nv, nOK := n[kw].(string)
+		switch {
+		case nOK && (!oOK || ov != nv):
+			add(true, "%s set to %q", kw, nv)
+		case oOK && !nOK:
+			add(false, "%s %q removed", kw, ov)
+		}
+	}
+	if ndep, _ := n["deprecated"].(bool); ndep {
+		if odep, _ := o["deprecated"].(bool); !odep {
+			add(false, "deprecated")
+		}
+	}
+
+	// properties
+	oprops, _ := o["properties"].(map[string]any)
+	nprops, _ := n["properties"].(map[string]any)
+	for _, k := range sortedKeys(oprops) {
+		if _, ok := nprops[k]; !ok {
+			out = append(out, Change{Path: joinPath(path, k), Detail: "property removed", Breaking: true})
+		}
+	}
+	for _, k := range sortedKeys(nprops) {
+		osub, ok := oprops[k]
+		if !ok {
+			out = append(out, Change{Path: joinPath(path, k), Detail: "property added"})
+			continue
+		}
+		om, _ := osub.(map[string]any)
+		nm, _ := nprops[k].(map[string]any)
+		out = append(out, d.diffNode(joinPath(path, k), om, nm)...)
+	}
+
+	// additionalProperties
+	oap, nap := o["additionalProperties"], n["additionalProperties"]
+	if nap == false && oap != false {
+		add(true, "additional properties no longer allowed")
+	} else if oap == false && nap != false {
+		add(false, "additional properties now allowed")
+	}
+	if om, ok := oap.(map[string]any); ok {
+		if nm, ok := nap.(map[string]any); ok {
+			out = append(out, d.diffNode(path+".*", om, nm)...)
+		}
+	}
+
+	// items
+	if om, ok := o["items"].(map[string]any); ok {
+		if nm, ok := n["items"].(map[string]any); ok {
+			out = append(out, d.diffNode(path+"[]", om, nm)...)
+		}
+	}
+
+	// composition: an extra allOf branch adds constraints, an extra
+	// oneOf/anyOf branch admits more values
+	for _, kw := range []string{"allOf", "oneOf", "anyOf"} {
+		ob, _ := o[kw].([]any)
+		nb, _ := n[kw].([]any)
+		for i := 0; i < max(len(ob), len(nb)); i++ {
+			bpath := fmt.Sprintf("%s/%s[%d]", path, kw, i)
+			switch {
+			case i >= len(ob):
+				out = append(out, Change{Path: bpath, Detail: "branch added", Breaking: kw == "allOf"})
+			case i >= len(nb):
+				out = append(out, Change{Path: bpath, Detail: "branch removed", Breaking: kw != "allOf"})
+			default:
+				om, _ := ob[i].(map[string]any)
+				nm, _ := nb[i].(map[string]any)
+				out = append(out, d.diffNode(bpath, om, nm)...)
+			}
+		}
+	}
+	return out
+}
+
+func diffBound(path, kw string, o, n map[string]any, lower bool) []Change {
+	ov, oOK := o[kw].(float64)
+	nv, nOK := n[kw].(float64)
+	switch {
+	case nOK && !oOK:
+		return []Change{{Path: path, Detail: fmt.Sprintf("%s set to %v", kw, nv), Breaking: true}}
+	case oOK && !nOK:
+		return []Change{{Path: path, Detail: fmt.Sprintf("%s %v removed", kw, ov)}}
+	case oOK && nOK && ov != nv:
+		tighter := nv < ov
+		if lower {
+			tighter = nv > ov
+		}
+		return []Change{{Path: path, Detail: fmt.Sprintf("%s changed from %v to %v", kw, ov, nv), Breaking: tighter}}
+	}
+	return nil
+}
+
+// schemaTypes returns the sorted "type" keyword values of a schema node.
+func schemaTypes(m map[string]any) []string {
+	out := declaredTypes(m)
+	sort.Strings(out)
+	return out
+}
+
+// declaredTypes returns the "type" keyword values of a schema node in the
+// order they are written.
+func declaredTypes(m map[string]any) []string {
+	var out []string
+	switch t := m["type"].(type) {
+	case string:
+		out = []string{t}
+	case []any:
+		for _, e := range t {
+			if s, ok := e.(string); ok {
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
+
+// typesCovered reports whether every old type is still accepted by the new types.
+func typesCovered(oldTypes, newTypes []string) bool {
+	accepted := map[string]bool{}
+	for _, t := range newTypes {
+		accepted[t] = true
+	}
+	for _, t := range oldTypes {
+		if !accepted[t] && !(t == "integer" && accepted["number"]) {
+			return false
+		}
+	}
+	return true
+}
+
+func stringSet(v any) map[string]bool {
+	out := map[string]bool{}
+	arr, _ := v.([]any)
+	for _, e := range arr {
+		if s, ok := e.(string); ok {
+			out[s] = true
+		}
+	}
+	return out
+}
+
+func sortedSet(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// missingValues returns the values of a that are not in b.
+func missingValues(a, b []any) []any {
+	var out []any
+	for _, av := range a {
+		found := false
+		for _, bv := range b {
+			if reflect.DeepEqual(av, bv) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			out = append(out, av)
+		}
+	}
+	return out
+}
+
+func formatValue(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
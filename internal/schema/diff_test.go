@@ -0,0 +1,157 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/yaml"
+)
+
+// yamlSchema decodes a schema written in YAML the way readSchemaJSON does.
+func yamlSchema(t *testing.T, src string) any {
+	t.Helper()
+	var v any
+	if err := yaml.Unmarshal([]byte(src), &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestDiffSchemas(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		want     []Change
+	}{
+		{
+			name: "unchanged",
+			old:  "type: object\nproperties:\n  a: {type: string}\n",
+			new:  "type: object\nproperties:\n  a: {type: string}\n",
+		},
+		{
+			name: "property added and removed",
+			old:  "properties:\n  a: {type: string}\n",
+			new:  "properties:\n  b: {type: string}\n",
+			want: []Change{
+				{Path: "a", Detail: "property removed", Breaking: true},
+				{Path: "b", Detail: "property added"},
+			},
+		},
+		{
+			name: "type widened and changed",
+			old:  "properties:\n  num: {type: integer}\n  s: {type: string}\n",
+			new:  "properties:\n  num: {type: number}\n  s: {type: boolean}\n",
+			want: []Change{
+				{Path: "num", Detail: "type widened from integer to number"},
+				{Path: "s", Detail: "type changed from string to boolean", Breaking: true},
+			},
+		},
+		{
+			name: "now required and enum narrowed",
+			old:  "properties:\n  env: {enum: [dev, prod]}\n",
+			new:  "required: [env]\nproperties:\n  env: {enum: [prod]}\n",
+			want: []Change{
+				{Path: "env", Detail: "now required", Breaking: true},
+				{Path: "env", Detail: `enum narrowed: removed ["dev"]`, Breaking: true},
+			},
+		},
+		{
+			name: "bounds and default",
+			old:  "properties:\n  r: {type: integer, minimum: 1, maximum: 10, default: 1}\n",
+			new:  "properties:\n  r: {type: integer, minimum: 2, maximum: 20, default: 2}\n",
+			want: []Change{
+				{Path: "r", Detail: "default changed from 1 to 2"},
+				{Path: "r", Detail: "minimum changed from 1 to 2", Breaking: true},
+				{Path: "r", Detail: "maximum changed from 10 to 20"},
+			},
+		},
+		{
+			name: "change inside a $ref definition",
+			old: `definitions:
+  res:
+    properties:
+      cpu: {type: string}
+properties:
+  resources: {$ref: "#/definitions/res"}
+`,
+			new: `definitions:
+  res:
+    properties:
+      cpu: {type: integer}
+properties:
+  resources: {$ref: "#/definitions/res"}
+`,
+			want: []Change{{Path: "resources.cpu", Detail: "type changed from string to integer", Breaking: true}},
+		},
+		{
+			name: "ref inlined without change",
+			old:  "definitions:\n  s: {type: string}\nproperties:\n  a: {$ref: \"#/definitions/s\"}\n",
+			new:  "properties:\n  a: {type: string}\n",
+		},
+		{
+			name: "recursive definition",
+			old:  "definitions:\n  node:\n    properties:\n      name: {type: string}\n      children: {type: array, items: {$ref: \"#/definitions/node\"}}\n$ref: \"#/definitions/node\"\n",
+			new:  "definitions:\n  node:\n    properties:\n      name: {type: integer}\n      children: {type: array, items: {$ref: \"#/definitions/node\"}}\n$ref: \"#/definitions/node\"\n",
+			want: []Change{{Path: "name", Detail: "type changed from string to integer", Breaking: true}},
+		},
+		{
+			name: "composition branches",
+			old: `properties:
+  port:
+    anyOf:
+      - {type: integer}
+      - {type: string}
+  app:
+    allOf:
+      - properties:
+          name: {type: string}
+`,
+			new: `properties:
+  port:
+    anyOf:
+      - {type: integer, maximum: 65535}
+  app:
+    allOf:
+      - properties:
+          name: {type: string, minLength: 1}
+      - required: [name]
+`,
+			want: []Change{
+				{Path: "app/allOf[0].name", Detail: "minLength set to 1", Breaking: true},
+				{Path: "app/allOf[1]", Detail: "branch added", Breaking: true},
+				{Path: "port/anyOf[0]", Detail: "maximum set to 65535", Breaking: true},
+				{Path: "port/anyOf[1]", Detail: "branch removed", Breaking: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diffSchemas(yamlSchema(t, tt.old), yamlSchema(t, tt.new))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("changes:\n%v\nwant:\n%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffSchemasIgnoresInvalidDefaults(t *testing.T) {
+	dir := t.TempDir()
+	oldPath := filepath.Join(dir, "old.schema.yaml")
+	newPath := filepath.Join(dir, "new.schema.yaml")
+	if err := os.WriteFile(oldPath, []byte("properties:\n  port: {type: integer, default: http}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(newPath, []byte("properties:\n  port: {type: integer, default: 80}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := DiffSchemas(oldPath, newPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Change{{Path: "port", Detail: `default changed from "http" to 80`}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("changes = %v, want %v", got, want)
+	}
+}
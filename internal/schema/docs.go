@@ -0,0 +1,306 @@
+package schema
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"strings"
+)
+
+// docRow is one property in the generated reference.
+type docRow struct {
+	Path        string
+	Depth       int
+	Type        string
+	Required    bool
+	Default     string
+	Enum        []string
+	Description string
+	Examples    []string
+	Deprecated  bool
+	// Recursive marks a $ref to a definition already being expanded at
+	// SameAs ("" for the root); its children are not listed again.
+	Recursive bool
+	SameAs    string
+}
+
+// RenderDocs renders a configuration reference for a schema (YAML or JSON) as
+// "markdown" or "html": one row per property (nested keys as dotted paths,
+// array items as "[]") with type, required flag, default, enum, description
+// and examples. Local $refs are resolved; a recursive one is listed once,
+// pointing back at the row that expands it.
+func RenderDocs(schemaPath, format string) ([]byte, error) {
+	sch, err := loadSchema(schemaPath)
+	if err != nil {
+		return nil, err
+	}
+	root, _ := sch.(map[string]any)
+	if root == nil {
+		return nil, fmt.Errorf("schema root must be an object")
+	}
+
+	d := &docWalker{root: root, seen: map[string]bool{}}
+	d.walk(root, "", 0)
+
+	title, _ := root["title"].(string)
+	desc, _ := root["description"].(string)
+	switch format {
+	case "markdown", "md":
+		return renderMarkdownDocs(title, desc, d.rows), nil
+	case "html":
+		return renderHTMLDocs(title, desc, d.rows), nil
+	}
+	return nil, fmt.Errorf("unknown docs format %q (want markdown or html)", format)
+}
+
+type docWalker struct {
+	root map[string]any
+	rows []docRow
+	seen map[string]bool
+	refs []docRef // $refs being expanded, to stop recursive definitions
+}
+
+type docRef struct {
+	ref  string
+	path string
+}
+
+// expanding returns the path where m's $ref is already being expanded.
+func (d *docWalker) expanding(m map[string]any) (string, bool) {
+	ref, _ := m["$ref"].(string)
+	for _, r := range d.refs {
+		if ref != "" && r.ref == ref {
+			return r.path, true
+		}
+	}
+	return "", false
+}
+
+// walk emits rows for the children of object/array schema m found at path.
+func (d *docWalker) walk(m map[string]any, path string, depth int) {
+	if _, ok := d.expanding(m); ok {
+		return
+	}
+	if ref, ok := m["$ref"].(string); ok {
+		d.refs = append(d.refs, docRef{ref, path})
+		defer func() { d.refs = d.refs[:len(d.refs)-1] }()
+		m = resolveRef(d.root, m)
+	}
+	required := stringSet(m["required"])
+
+	props, _ := m["properties"].(map[string]any)
+	for _, k := range sortedKeys(props) {
+		sub, ok := props[k].(map[string]any)
+		if !ok {
+			continue
+		}
+		d.add(sub, joinPath(path, k), depth, required[k])
+	}
+	if aps, ok := m["additionalProperties"].(map[string]any); ok {
+		d.add(aps, path+".*", depth, false)
+	}
+	if items, ok := m["items"].(map[string]any); ok {
+		resolved := resolveRef(d.root, items)
+		if _, hasProps := resolved["properties"]; hasProps || resolved["items"] != nil {
+			d.walk(items, path+"[]", depth)
+		}
+	}
+	// Composed schemas contribute their properties at the same level.
+	for _, kw := range []string{"allOf", "anyOf", "oneOf"} {
+		branches, _ := m[kw].([]any)
+		for _, b := range branches {
+			if bm, ok := b.(map[string]any); ok {
+				d.walk(bm, path, depth)
+			}
+		}
+	}
+}
+
+// add emits the row for property schema sub (not yet resolved) at path, then
+// the rows of its children.
+func (d *docWalker) add(sub map[string]any, path string, depth int, required bool) {
+	if d.seen[path] {
+		return
+	}
+	d.seen[path] = true
+
+	m := resolveRef(d.root, sub)
+	row := docRow{Path: path, Depth: depth, Type: docType(d.root, m), Required: required}
+	if v, ok := m["default"]; ok {
+		row.Default = formatValue(v)
+	}
+	if enum, ok := m["enum"].([]any); ok {
+		for _, e := range enum {
+			row.Enum = append(row.Enum, formatValue(e))
+		}
+	} else if c, ok := m["const"]; ok {
+		row.Enum = []string{formatValue(c)}
+	}
+	row.Description, _ = m["description"].(string)
+	if t, ok := m["title"].(string); ok && row.Description == "" {
+		row.Description = t
+	}
+	row.Deprecated, _ = m["deprecated"].(bool)
+	if ex, ok := m["examples"].([]any); ok {
+		for _, e := range ex {
+			row.Examples = append(row.Examples, formatValue(e))
+		}
+	}
+	row.SameAs, row.Recursive = d.expanding(sub)
+	d.rows = append(d.rows, row)
+	if !row.Recursive {
+		d.walk(sub, path, depth+1)
+	}
+}
+
+// docType describes a schema's type for humans, e.g. "string (email)",
+// "array of object" or "string | integer".
+func docType(root, m map[string]any) string {
+	types := schemaTypes(m)
+	if len(types) == 0 {
+		switch {
+		case m["properties"] != nil:
+			types = []string{"object"}
+		case m["items"] != nil:
+			types = []string{"array"}
+		}
+	}
+	if len(types) == 0 {
+		var alts []string
+		for _, kw := range []string{"oneOf", "anyOf"} {
+			branches, _ := m[kw].([]any)
+			for _, b := range branches {
+				if bm, ok := b.(map[string]any); ok {
+					if t := docType(root, resolveRef(root, bm)); t != "" {
+						alts = append(alts, t)
+					}
+				}
+			}
+		}
+		return strings.Join(alts, " | ")
+	}
+	out := strings.Join(types, " | ")
+	if len(types) == 1 && types[0] == "array" {
+		if items, ok := m["items"].(map[string]any); ok {
+			if it := docType(root, resolveRef(root, items)); it != "" {
+				out += " of " + it
+			}
+		}
+	}
+	if f, ok := m["format"].(string); ok {
+		out += " (" + f + ")"
+	}
+	return out
+}
+
+func renderMarkdownDocs(title, desc string, rows []docRow) []byte {
+	var b bytes.Buffer
+	if title == "" {
+		title = "Configuration reference"
+	}
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	if desc != "" {
+		fmt.Fprintf(&b, "%s\n\n", desc)
+	}
+	b.WriteString("| Key | Type | Required | Default | Enum | Description | Examples |\n")
+	b.WriteString("|-----|------|----------|---------|------|-------------|----------|\n")
+	for _, r := range rows {
+		req := "no"
+		if r.Required {
+			req = "yes"
+		}
+		desc := mdCell(r.Description)
+		if r.Recursive {
+			desc = strings.TrimSpace(fmt.Sprintf("Recursive: same as %s. %s", mdRef(r.SameAs), desc))
+		}
+		if r.Deprecated {
+			desc = strings.TrimSpace("**Deprecated.** " + desc)
+		}
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %s | %s | %s | %s |\n",
+			r.Path, mdCell(r.Type), req, mdCode(r.Default), mdCodeList(r.Enum), desc, mdCodeList(r.Examples))
+	}
+	return b.Bytes()
+}
+
+func mdCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(strings.TrimSpace(s), "\n", "<br>")
+}
+
+func mdCode(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "`" + mdCell(s) + "`"
+}
+
+func mdRef(path string) string {
+	if path == "" {
+		return "the root"
+	}
+	return mdCode(path)
+}
+
+func mdCodeList(vals []string) string {
+	out := make([]string, len(vals))
+	for i, v := range vals {
+		out[i] = mdCode(v)
+	}
+	return strings.Join(out, ", ")
+}
+
+func renderHTMLDocs(title, desc string, rows []docRow) []byte {
+	var b bytes.Buffer
+	if title == "" {
+		title = "Configuration reference"
+	}
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&b, "<title>%s</title>\n", html.EscapeString(title))
+	b.WriteString("<style>table{border-collapse:collapse}th,td{border:1px solid #ccc;padding:4px 8px;vertical-align:top}code{white-space:pre-wrap}</style>\n")
+	b.WriteString("</head>\n<body>\n")
+	fmt.Fprintf(&b, "<h1>%s</h1>\n", html.EscapeString(title))
+	if desc != "" {
+		fmt.Fprintf(&b, "<p>%s</p>\n", html.EscapeString(desc))
+	}
+	b.WriteString("<table>\n<thead><tr><th>Key</th><th>Type</th><th>Required</th><th>Default</th><th>Enum</th><th>Description</th><th>Examples</th></tr></thead>\n<tbody>\n")
+	for _, r := range rows {
+		req := "no"
+		if r.Required {
+			req = "yes"
+		}
+		desc := strings.ReplaceAll(html.EscapeString(r.Description), "\n", "<br>")
+		if r.Recursive {
+			desc = strings.TrimSpace(fmt.Sprintf("Recursive: same as %s. %s", htmlRef(r.SameAs), desc))
+		}
+		if r.Deprecated {
+			desc = strings.TrimSpace("<strong>Deprecated.</strong> " + desc)
+		}
+		fmt.Fprintf(&b, "<tr><td style=\"padding-left:%dem\"><code>%s</code></td><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>\n",
+			r.Depth+1, html.EscapeString(r.Path), html.EscapeString(r.Type), req, htmlCode(r.Default), htmlCodeList(r.Enum),
+			desc, htmlCodeList(r.Examples))
+	}
+	b.WriteString("</tbody>\n</table>\n</body>\n</html>\n")
+	return b.Bytes()
+}
+
+func htmlCode(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "<code>" + html.EscapeString(s) + "</code>"
+}
+
+func htmlRef(path string) string {
+	if path == "" {
+		return "the root"
+	}
+	return htmlCode(path)
+}
+
+func htmlCodeList(vals []string) string {
+	out := make([]string, len(vals))
+	for i, v := range vals {
+		out[i] = htmlCode(v)
+	}
+	return strings.Join(out, ", ")
+}
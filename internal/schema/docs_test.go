@@ -0,0 +1,104 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderDocs(t *testing.T) {
+	const header = "| Key | Type | Required | Default | Enum | Description | Examples |\n|-----|------|----------|---------|------|-------------|----------|\n"
+	tests := []struct {
+		name    string
+		schema  string
+		format  string
+		want    string // markdown: the whole output; html: a substring
+		wantErr string
+	}{
+		{
+			name:   "title, required, default, enum and examples",
+			schema: "title: App config\ndescription: Settings.\ntype: object\nrequired: [name]\nproperties:\n  name: {type: string, description: \"App | name\", examples: [api]}\n  env: {type: string, enum: [dev, prod], default: dev}\n",
+			format: "markdown",
+			want: "# App config\n\nSettings.\n\n" + header +
+				"| `env` | string | no | `\"dev\"` | `\"dev\"`, `\"prod\"` |  |  |\n" +
+				"| `name` | string | yes |  |  | App \\| name | `\"api\"` |\n",
+		},
+		{
+			name:   "refs and array items",
+			schema: "definitions:\n  res:\n    type: object\n    properties:\n      cpu: {type: string, default: 100m}\nproperties:\n  resources: {$ref: \"#/definitions/res\"}\n  ports:\n    type: array\n    items:\n      type: object\n      properties:\n        port: {type: integer}\n",
+			format: "md",
+			want: "# Configuration reference\n\n" + header +
+				"| `ports` | array of object | no |  |  |  |  |\n" +
+				"| `ports[].port` | integer | no |  |  |  |  |\n" +
+				"| `resources` | object | no |  |  |  |  |\n" +
+				"| `resources.cpu` | string | no | `\"100m\"` |  |  |  |\n",
+		},
+		{
+			name:   "html escapes values",
+			schema: "properties:\n  name: {type: string, description: \"<b>name</b>\"}\n",
+			format: "html",
+			want:   "<td>&lt;b&gt;name&lt;/b&gt;</td>",
+		},
+		{
+			name:   "recursive refs are listed once",
+			schema: "definitions:\n  node:\n    type: object\n    properties:\n      name: {type: string}\n      left: {$ref: \"#/definitions/node\"}\n      right: {$ref: \"#/definitions/node\", description: Right branch.}\n$ref: \"#/definitions/node\"\n",
+			format: "markdown",
+			want: "# Configuration reference\n\n" + header +
+				"| `left` | object | no |  |  | Recursive: same as the root. |  |\n" +
+				"| `name` | string | no |  |  |  |  |\n" +
+				"| `right` | object | no |  |  | Recursive: same as the root. Right branch. |  |\n",
+		},
+		{
+			name:   "deprecated in markdown",
+			schema: "properties:\n  old: {type: string, deprecated: true, description: Use new.}\n",
+			format: "markdown",
+			want:   "# Configuration reference\n\n" + header + "| `old` | string | no |  |  | **Deprecated.** Use new. |  |\n",
+		},
+		{
+			name:   "deprecated in html",
+			schema: "properties:\n  old: {type: string, deprecated: true, description: Use new.}\n",
+			format: "html",
+			want:   "<td><strong>Deprecated.</strong> Use new.</td>",
+		},
+		{
+			name:   "recursive ref in html",
+			schema: "definitions:\n  node:\n    properties:\n      next: {$ref: \"#/definitions/node\"}\nproperties:\n  list: {$ref: \"#/definitions/node\"}\n",
+			format: "html",
+			want:   "<td>Recursive: same as <code>list</code>.</td>",
+		},
+		{
+			name:    "unknown format",
+			schema:  "properties: {}\n",
+			format:  "pdf",
+			wantErr: `unknown docs format "pdf"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := filepath.Join(t.TempDir(), "schema.yaml")
+			if err := os.WriteFile(p, []byte(tt.schema), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			out, err := RenderDocs(p, tt.format)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.format == "html" {
+				if !strings.Contains(string(out), tt.want) {
+					t.Errorf("output:\n%s\nwant it to contain %q", out, tt.want)
+				}
+				return
+			}
+			if string(out) != tt.want {
+				t.Errorf("output:\n%s\nwant:\n%s", out, tt.want)
+			}
+		})
+	}
+}
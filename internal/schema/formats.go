@@ -0,0 +1,226 @@
+package schema
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// Kubernetes-style formats gojsonschema does not know. They are registered
+// with gojsonschema for every validation path (config, values, schema
+// defaults and examples).
+func init() {
+	for name, fn := range map[string]func(string) bool{
+		"dns-1123-label": isDNS1123Label,
+		"k8s-name":       isK8sName,
+		"quantity":       quantityRe.MatchString,
+		"duration":       isDuration,
+		"image-ref":      imageRefRe.MatchString,
+		"cron":           isCron,
+	} {
+		if err := RegisterFormat(name, fn); err != nil {
+			panic(err)
+		}
+	}
+}
+
+var (
+	formatsMu sync.Mutex
+	// customFormats records the formats added on top of gojsonschema's: ""
+	// for a Go checker, the regular expression for an x-formats one.
+	customFormats = map[string]string{}
+)
+
+// RegisterFormat makes "format": name check string values with fn (other
+// JSON types always pass, as for the standard formats). The standard formats
+// and formats already registered cannot be replaced.
+func RegisterFormat(name string, fn func(string) bool) error {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+	if _, ok := customFormats[name]; ok {
+		return fmt.Errorf("format %q is already registered", name)
+	}
+	if gojsonschema.FormatCheckers.Has(name) {
+		return fmt.Errorf("format %q is a standard format and cannot be replaced", name)
+	}
+	customFormats[name] = ""
+	gojsonschema.FormatCheckers.Add(name, stringFormat(fn))
+	return nil
+}
+
+type stringFormat func(string) bool
+
+func (f stringFormat) IsFormat(input any) bool {
+	s, ok := input.(string)
+	return !ok || f(s)
+}
+
+// registerSchemaFormats registers the regular-expression formats a schema
+// declares at its root:
+//
+//	x-formats:
+//	  semver: '^v?[0-9]+\.[0-9]+\.[0-9]+$'
+//
+// Formats are global to the process, so a name that is built in, registered
+// in Go or defined with a different expression by another schema is an
+// error; loading the same definition again is not.
+func registerSchemaFormats(sch any) error {
+	root, _ := sch.(map[string]any)
+	xf, ok := root["x-formats"]
+	if !ok {
+		return nil
+	}
+	formats, ok := xf.(map[string]any)
+	if !ok {
+		return fmt.Errorf("x-formats: must be a map of format name to regular expression")
+	}
+	for _, name := range sortedKeys(formats) {
+		p, ok := formats[name].(string)
+		if !ok {
+			return fmt.Errorf("x-formats/%s: must be a regular expression string", name)
+		}
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return fmt.Errorf("x-formats/%s: %w", name, err)
+		}
+		if err := registerPatternFormat(name, p, re); err != nil {
+			return fmt.Errorf("x-formats/%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func registerPatternFormat(name, pattern string, re *regexp.Regexp) error {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+	if prev, ok := customFormats[name]; ok {
+		switch {
+		case prev == pattern:
+			return nil
+		case prev == "":
+			return fmt.Errorf("format %q is built into valuesctl and cannot be redefined", name)
+		default:
+			return fmt.Errorf("format %q is already defined as %q", name, prev)
+		}
+	}
+	if gojsonschema.FormatCheckers.Has(name) {
+		return fmt.Errorf("format %q is a standard format and cannot be redefined", name)
+	}
+	customFormats[name] = pattern
+	gojsonschema.FormatCheckers.Add(name, stringFormat(re.MatchString))
+	return nil
+}
+
+const hostLabel = `[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?`
+
+var (
+	dns1123LabelRe = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+	// a number with an optional binary (Ki..Ei), decimal (n..E) or exponent suffix
+	quantityRe = regexp.MustCompile(`^[+-]?([0-9]+(\.[0-9]*)?|\.[0-9]+)(Ki|Mi|Gi|Ti|Pi|Ei|n|u|m|k|M|G|T|P|E|[eE][+-]?[0-9]+)?$`)
+
+	// [registry[:port]/]path[:tag][@digest]; as in Docker, a first component
+	// is a registry only if it has a "." or port or is localhost
+	imageRefRe = regexp.MustCompile(`^` +
+		`((localhost|` + hostLabel + `(\.` + hostLabel + `)+)(:[0-9]+)?/|` + hostLabel + `:[0-9]+/)?` +
+		`[a-z0-9]+(([._]|__|-+)[a-z0-9]+)*(/[a-z0-9]+(([._]|__|-+)[a-z0-9]+)*)*` +
+		`(:[a-zA-Z0-9_][a-zA-Z0-9_.-]{0,127})?` +
+		`(@[a-zA-Z][a-zA-Z0-9]*([-_+.][a-zA-Z][a-zA-Z0-9]*)*:[0-9a-fA-F]{32,})?$`)
+)
+
+// isDNS1123Label checks a DNS-1123 label (e.g. a namespace or service name).
+func isDNS1123Label(s string) bool {
+	return len(s) <= 63 && dns1123LabelRe.MatchString(s)
+}
+
+// isK8sName checks a DNS-1123 subdomain, the rule for most object names.
+func isK8sName(s string) bool {
+	if len(s) == 0 || len(s) > 253 {
+		return false
+	}
+	for _, l := range strings.Split(s, ".") {
+		if !isDNS1123Label(l) {
+			return false
+		}
+	}
+	return true
+}
+
+// isDuration checks a Go/Kubernetes duration such as "30s" or "1h30m".
+func isDuration(s string) bool {
+	_, err := time.ParseDuration(s)
+	return err == nil
+}
+
+var (
+	cronMonths = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+	cronDays   = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+	cronMacros = map[string]bool{
+		"@yearly": true, "@annually": true, "@monthly": true, "@weekly": true,
+		"@daily": true, "@midnight": true, "@hourly": true,
+	}
+)
+
+// isCron checks a five-field cron schedule (minute hour day-of-month month
+// day-of-week, as in a CronJob) or one of the @hourly-style macros.
+func isCron(s string) bool {
+	s = strings.TrimSpace(s)
+	if cronMacros[strings.ToLower(s)] {
+		return true
+	}
+	if rest, ok := strings.CutPrefix(s, "@every "); ok {
+		return isDuration(strings.TrimSpace(rest))
+	}
+	fields := strings.Fields(s)
+	if len(fields) != 5 {
+		return false
+	}
+	return cronField(fields[0], 0, 59, nil) &&
+		cronField(fields[1], 0, 23, nil) &&
+		cronField(fields[2], 1, 31, nil) &&
+		cronField(fields[3], 1, 12, cronMonths) &&
+		cronField(fields[4], 0, 7, cronDays)
+}
+
+// cronField checks a comma-separated list of "*", "?", n or n-m, each with an
+// optional "/step"; names (jan, mon) stand for lo + their index.
+func cronField(f string, lo, hi int, names []string) bool {
+	for _, item := range strings.Split(f, ",") {
+		rng, step, hasStep := strings.Cut(item, "/")
+		if hasStep {
+			if n, err := strconv.Atoi(step); err != nil || n < 1 {
+				return false
+			}
+		}
+		if rng == "*" || rng == "?" {
+			continue
+		}
+		a, b, isRange := strings.Cut(rng, "-")
+		from, ok := cronValue(a, lo, hi, names)
+		if !ok {
+			return false
+		}
+		if isRange {
+			to, ok := cronValue(b, lo, hi, names)
+			if !ok || to < from {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func cronValue(v string, lo, hi int, names []string) (int, bool) {
+	for i, n := range names {
+		if strings.EqualFold(v, n) {
+			return lo + i, true
+		}
+	}
+	n, err := strconv.Atoi(v)
+	return n, err == nil && n >= lo && n <= hi
+}
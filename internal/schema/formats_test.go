@@ -0,0 +1,98 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+func TestFormats(t *testing.T) {
+	tests := []struct {
+		format string
+		value  any
+		want   bool
+	}{
+		{"dns-1123-label", "web-1", true},
+		{"dns-1123-label", "Web", false},
+		{"dns-1123-label", strings.Repeat("a", 64), false},
+		{"k8s-name", "my-app.example", true},
+		{"k8s-name", "-app", false},
+		{"quantity", "500m", true},
+		{"quantity", "1.5Gi", true},
+		{"quantity", "1GB", false},
+		{"duration", "1h30m", true},
+		{"duration", "10", false},
+		{"image-ref", "ghcr.io/org/app:1.2", true},
+		{"image-ref", "app@sha256:" + strings.Repeat("a", 64), true},
+		{"image-ref", "App:latest", false},
+		{"cron", "*/5 * * * *", true},
+		{"cron", "* * *", false},
+		{"duration", 10, true}, // non-strings are left to "type"
+	}
+	for _, tt := range tests {
+		if got := gojsonschema.FormatCheckers.IsFormat(tt.format, tt.value); got != tt.want {
+			t.Errorf("%s %#v: IsFormat = %v, want %v", tt.format, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestRegisterFormatRefusesTakenNames(t *testing.T) {
+	for _, name := range []string{"email", "date-time", "k8s-name"} {
+		if err := RegisterFormat(name, func(string) bool { return true }); err == nil {
+			t.Errorf("RegisterFormat(%q) succeeded, want an error", name)
+		}
+	}
+	if gojsonschema.FormatCheckers.IsFormat("email", "not an email") {
+		t.Error("the built-in email checker was replaced")
+	}
+}
+
+func TestSchemaFormats(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		wantErr string
+	}{
+		{name: "new format", schema: "x-formats:\n  test-semver: '^v[0-9]+$'\n"},
+		{name: "same definition again", schema: "x-formats:\n  test-semver: '^v[0-9]+$'\n"},
+		{name: "conflicting definition", schema: "x-formats:\n  test-semver: '^[0-9]+$'\n", wantErr: `x-formats/test-semver: format "test-semver" is already defined as "^v[0-9]+$"`},
+		{name: "standard format", schema: "x-formats:\n  email: '.*'\n", wantErr: `x-formats/email: format "email" is a standard format and cannot be redefined`},
+		{name: "Go format", schema: "x-formats:\n  cron: '.*'\n", wantErr: `x-formats/cron: format "cron" is built into valuesctl and cannot be redefined`},
+		{name: "invalid expression", schema: "x-formats:\n  test-bad: '('\n", wantErr: "x-formats/test-bad: error parsing regexp"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := registerSchemaFormats(yamlSchema(t, tt.schema))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSchemaFormatValidation(t *testing.T) {
+	dir := t.TempDir()
+	schemaPath := filepath.Join(dir, "config.schema.yaml")
+	valuesPath := filepath.Join(dir, "values.yaml")
+	schema := "x-formats:\n  test-release: '^r[0-9]+$'\nproperties:\n  release: {type: string, format: test-release}\n"
+	if err := os.WriteFile(schemaPath, []byte(schema), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for values, ok := range map[string]bool{"release: r12\n": true, "release: v12\n": false} {
+		if err := os.WriteFile(valuesPath, []byte(values), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := ValidateYAMLWithSchema(schemaPath, valuesPath); (err == nil) != ok {
+			t.Errorf("%q: err = %v, want valid = %v", values, err, ok)
+		}
+	}
+}
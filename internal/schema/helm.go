@@ -0,0 +1,166 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/besrabasant/valuesctl/internal/tmpl"
+	"sigs.k8s.io/yaml"
+)
+
+// keywords copied from a config property onto the values.yaml leaf it renders into
+var helmLeafKeywords = []string{
+	"type", "enum", "const", "format", "pattern", "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum",
+	"multipleOf", "minLength", "maxLength", "title", "description", "deprecated",
+}
+
+// BuildHelmValuesSchema emits a Helm-compatible values.schema.json describing
+// the values.yaml produced by rendering tplPath with a sample config built from
+// the config schema. The shape and types come from the rendered output; where
+// a values.yaml leaf is exactly a config value, that property's type, enum,
+// format, bounds and description are propagated from the config schema.
+func BuildHelmValuesSchema(schemaPath, tplPath string) ([]byte, error) {
+	sch, err := loadSchema(schemaPath)
+	if err != nil {
+		return nil, err
+	}
+	root, _ := sch.(map[string]any)
+
+	sample, _ := toMapStringAny(sampleForSchemaPlain(sch, SampleOptions{root: root}))
+	if sample == nil {
+		sample = map[string]any{}
+	}
+	rendered, err := tmpl.RenderWithData(tplPath, sample)
+	if err != nil {
+		return nil, fmt.Errorf("render template with sample config: %w", err)
+	}
+	node, err := inferSchemaNode(rendered)
+	if err != nil {
+		return nil, fmt.Errorf("rendered values: %w", err)
+	}
+	inferredAny, err := nodeToJSONValue(node)
+	if err != nil {
+		return nil, err
+	}
+	out, _ := inferredAny.(map[string]any)
+	// rendered sample values are placeholders, not chart defaults
+	walkSubschemas(out, "", func(_ string, m map[string]any) bool {
+		delete(m, "default")
+		return true
+	})
+
+	// Render again with every string/number config leaf replaced by a unique
+	// marker, to learn which values.yaml leaves come straight from the config.
+	// Templates that compare or compute on values may fail here; the inferred
+	// schema is still usable, just without propagated config metadata.
+	probes := map[string]map[string]any{}
+	probeData, _ := toMapStringAny(probeConfig(root, root, sample, probes))
+	if probeYAML, err := tmpl.RenderWithData(tplPath, probeData); err == nil {
+		var probeValues any
+		if err := yaml.Unmarshal(probeYAML, &probeValues); err == nil {
+			overlayProbes(out, probeValues, probes)
+		}
+	}
+
+	b, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("json encode: %w", err)
+	}
+	return append(b, '\n'), nil
+}
+
+// probeConfig mirrors cfg, replacing string and number leaves with markers
+// recorded in probes together with the config subschema they belong to.
+func probeConfig(root, sch map[string]any, cfg any, probes map[string]map[string]any) any {
+	if sch != nil {
+		sch = resolveRef(root, sch)
+	}
+	switch v := cfg.(type) {
+	case map[string]any:
+		props, _ := sch["properties"].(map[string]any)
+		aps, _ := sch["additionalProperties"].(map[string]any)
+		out := make(map[string]any, len(v))
+		for k, e := range v {
+			sub, _ := props[k].(map[string]any)
+			if sub == nil {
+				sub = aps
+			}
+			out[k] = probeConfig(root, sub, e, probes)
+		}
+		return out
+	case []any:
+		items, _ := sch["items"].(map[string]any)
+		out := make([]any, len(v))
+		for i, e := range v {
+			out[i] = probeConfig(root, items, e, probes)
+		}
+		return out
+	case string, float64, int:
+		if sch == nil {
+			return v
+		}
+		marker := fmt.Sprintf("__valuesctl_probe_%d__", len(probes))
+		probes[marker] = sch
+		return marker
+	}
+	return cfg
+}
+
+// overlayProbes walks the probe render alongside the inferred values schema and
+// enriches leaves whose value is exactly a probe marker.
+func overlayProbes(sch map[string]any, probe any, probes map[string]map[string]any) {
+	switch p := probe.(type) {
+	case map[string]any:
+		props, _ := sch["properties"].(map[string]any)
+		for k, v := range p {
+			sub, ok := props[k].(map[string]any)
+			if !ok {
+				continue
+			}
+			if s, ok := v.(string); ok {
+				if cfgSchema, ok := probes[strings.TrimSpace(s)]; ok {
+					props[k] = helmLeafSchema(sub, cfgSchema)
+					continue
+				}
+			}
+			overlayProbes(sub, v, probes)
+		}
+	case []any:
+		items, ok := sch["items"].(map[string]any)
+		if !ok {
+			return
+		}
+		for _, e := range p {
+			overlayProbes(items, e, probes)
+		}
+	}
+}
+
+// helmLeafSchema merges config metadata into an inferred leaf. The config's
+// type constraints only apply when the rendered value has a compatible type
+// (a quoted number, say, stays a string).
+func helmLeafSchema(inferredLeaf, cfgSchema map[string]any) map[string]any {
+	out := map[string]any{}
+	for k, v := range inferredLeaf {
+		out[k] = v
+	}
+	inferredTypes := schemaTypes(inferredLeaf)
+	compatible := true
+	for _, t := range inferredTypes {
+		if !typeAllows(schemaTypes(cfgSchema), t) {
+			compatible = false
+		}
+	}
+	for _, kw := range helmLeafKeywords {
+		v, ok := cfgSchema[kw]
+		if !ok {
+			continue
+		}
+		if !compatible && kw != "title" && kw != "description" && kw != "deprecated" {
+			continue
+		}
+		out[kw] = cloneJSON(v)
+	}
+	return out
+}
@@ -0,0 +1,75 @@
+package schema
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestBuildHelmValuesSchema(t *testing.T) {
+	const configSchema = `type: object
+properties:
+  app:
+    type: object
+    properties:
+      name: {type: string, default: api, description: App name, pattern: "^[a-z]+$"}
+      replicas: {type: integer, default: 2, minimum: 1}
+      env: {type: string, enum: [dev, prod], default: dev}
+`
+	tests := []struct {
+		name     string
+		template string
+		want     string // JSON
+	}{
+		{
+			name:     "leaves copied from the config get its metadata",
+			template: "nameOverride: {{ .app.name }}\nreplicaCount: {{ .app.replicas }}\nenv: {{ .app.env }}\n",
+			want: `{"$schema": "http://json-schema.org/draft-07/schema#", "type": "object", "properties": {
+				"nameOverride": {"type": "string", "description": "App name", "pattern": "^[a-z]+$"},
+				"replicaCount": {"type": "integer", "minimum": 1},
+				"env": {"type": "string", "enum": ["dev", "prod"]}}}`,
+		},
+		{
+			name:     "computed and literal values are only inferred",
+			template: "image:\n  tag: \"v-{{ .app.env }}\"\n  pullPolicy: IfNotPresent\nreplicaCount: {{ len .app.name }}\n",
+			want: `{"$schema": "http://json-schema.org/draft-07/schema#", "type": "object", "properties": {
+				"image": {"type": "object", "properties": {"tag": {"type": "string"}, "pullPolicy": {"type": "string"}}},
+				"replicaCount": {"type": "integer"}}}`,
+		},
+		{
+			name:     "quoted number keeps its rendered type",
+			template: "replicaCount: \"{{ .app.replicas }}\"\n",
+			want: `{"$schema": "http://json-schema.org/draft-07/schema#", "type": "object", "properties": {
+				"replicaCount": {"type": "string"}}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			schemaPath := filepath.Join(dir, "config.schema.yaml")
+			tplPath := filepath.Join(dir, "values.tmpl")
+			if err := os.WriteFile(schemaPath, []byte(configSchema), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(tplPath, []byte(tt.template), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			out, err := BuildHelmValuesSchema(schemaPath, tplPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got, want any
+			if err := json.Unmarshal(out, &got); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("schema:\n%s\nwant:\n%s", out, tt.want)
+			}
+		})
+	}
+}
@@ -4,16 +4,76 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 	"unicode/utf8"
+
+	"sigs.k8s.io/yaml"
 )
 
+// readSchemaJSON reads a schema authored in YAML or JSON and returns it as JSON bytes.
+func readSchemaJSON(schemaPath string) ([]byte, error) {
+	raw, err := os.ReadFile(schemaPath)
+	if err != nil {
+		return nil, fmt.Errorf("read schema: %w", err)
+	}
+	if !looksLikeYAML(schemaPath, raw) {
+		return raw, nil
+	}
+	out, err := yaml.YAMLToJSON(raw)
+	if err != nil {
+		return nil, fmt.Errorf("schema YAML->JSON: %w", err)
+	}
+	return out, nil
+}
+
+// loadSchema reads a schema (YAML or JSON) into generic JSON values.
+func loadSchema(schemaPath string) (any, error) {
+	schemaJSON, err := readSchemaJSON(schemaPath)
+	if err != nil {
+		return nil, err
+	}
+	return parseSchema(schemaJSON)
+}
+
+// decodeSchema reads a schema (YAML or JSON) into generic JSON values without
+// registering its formats or checking its values, for tools that only compare
+// or inspect schemas.
+func decodeSchema(schemaPath string) (any, error) {
+	schemaJSON, err := readSchemaJSON(schemaPath)
+	if err != nil {
+		return nil, err
+	}
+	var sch any
+	if err := json.Unmarshal(schemaJSON, &sch); err != nil {
+		return nil, fmt.Errorf("schema json unmarshal: %w", err)
+	}
+	return sch, nil
+}
+
+// parseSchema decodes schema JSON, registers its "x-formats" and checks its
+// defaults, examples and enums, so no command samples or injects a value the
+// schema itself rejects.
+func parseSchema(schemaJSON []byte) (any, error) {
+	var sch any
+	if err := json.Unmarshal(schemaJSON, &sch); err != nil {
+		return nil, fmt.Errorf("schema json unmarshal: %w", err)
+	}
+	if err := registerSchemaFormats(sch); err != nil {
+		return nil, err
+	}
+	if err := checkSchemaValues(sch); err != nil {
+		return nil, err
+	}
+	return sch, nil
+}
+
 // looksLikeYAML decides if the payload should be treated as YAML (true) or JSON (false).
 // Rules:
-//   1) Extension hint: .json => JSON, .yaml/.yml => YAML
-//   2) Sniff first non-whitespace rune (after BOM): '{' or '[' => JSON
-//   3) Try JSON unmarshal: success => JSON, else => YAML
+//  1. Extension hint: .json => JSON, .yaml/.yml => YAML
+//  2. Sniff first non-whitespace rune (after BOM): '{' or '[' => JSON
+//  3. Try JSON unmarshal: success => JSON, else => YAML
 func looksLikeYAML(path string, b []byte) bool {
 	ext := strings.ToLower(filepath.Ext(path))
 	switch ext {
@@ -55,7 +115,6 @@ func stripUTF8BOM(b []byte) []byte {
 	return b
 }
 
-
 func toMapStringAny(v any) (map[string]any, bool) {
 	switch t := v.(type) {
 	case map[string]any:
@@ -71,9 +130,115 @@ func toMapStringAny(v any) (map[string]any, bool) {
 	}
 }
 
+// joinPath appends a key to a dotted config path.
+func joinPath(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
 func cloneJSON(v any) any {
 	b, _ := json.Marshal(v)
 	var out any
 	_ = json.Unmarshal(b, &out)
 	return out
-}
\ No newline at end of file
+}
+
+// resolveRef follows a local "$ref" ("#/definitions/x") against root, merging
+// sibling keywords (e.g. a property-specific description) over the target.
+// Non-local or broken refs leave the node unchanged.
+func resolveRef(root map[string]any, m map[string]any) map[string]any {
+	for depth := 0; depth < 32; depth++ {
+		ref, ok := m["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#") {
+			return m
+		}
+		var target any = root
+		for _, seg := range strings.Split(strings.TrimPrefix(ref, "#"), "/")[1:] {
+			seg = strings.ReplaceAll(strings.ReplaceAll(seg, "~1", "/"), "~0", "~")
+			tm, ok := target.(map[string]any)
+			if !ok {
+				return m
+			}
+			target = tm[seg]
+		}
+		tm, ok := target.(map[string]any)
+		if !ok {
+			return m
+		}
+		merged := make(map[string]any, len(tm)+len(m))
+		for k, v := range tm {
+			merged[k] = v
+		}
+		for k, v := range m {
+			if k != "$ref" {
+				merged[k] = v
+			}
+		}
+		m = merged
+	}
+	return m
+}
+
+// mergeAllOf folds the allOf branches of m into a single schema so callers see
+// one object: properties are united (a property declared in several branches
+// becomes an allOf of its declarations, merged in turn when it is visited),
+// required lists are joined, and other keywords (description, type, default,
+// ...) are taken from m itself, else from the first branch that has them.
+// Branches may be local $refs into root.
+func mergeAllOf(root, m map[string]any) map[string]any {
+	branches, ok := m["allOf"].([]any)
+	if !ok || len(branches) == 0 {
+		return m
+	}
+	out := without(m, "allOf")
+	props := map[string]any{}
+	if p, ok := m["properties"].(map[string]any); ok {
+		for k, v := range p {
+			props[k] = v
+		}
+	}
+	required := stringList(m["required"])
+	for _, b := range branches {
+		bm, ok := b.(map[string]any)
+		if !ok {
+			continue
+		}
+		bm = mergeAllOf(root, resolveRef(root, bm))
+		for k, v := range bm {
+			switch k {
+			case "properties":
+				p, _ := v.(map[string]any)
+				for pk, pv := range p {
+					if prev, exists := props[pk]; exists {
+						props[pk] = map[string]any{"allOf": []any{prev, pv}}
+					} else {
+						props[pk] = pv
+					}
+				}
+			case "required":
+				required = append(required, stringList(v)...)
+			default:
+				if _, exists := out[k]; !exists {
+					out[k] = v
+				}
+			}
+		}
+	}
+	if len(props) > 0 {
+		out["properties"] = props
+	}
+	if len(required) > 0 {
+		seen := map[string]bool{}
+		req := make([]any, 0, len(required))
+		for _, r := range required {
+			if !seen[r] {
+				seen[r] = true
+				req = append(req, r)
+			}
+		}
+		out["required"] = req
+	}
+	return out
+}
@@ -0,0 +1,195 @@
+package schema
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	y3 "gopkg.in/yaml.v3"
+)
+
+// InferSchemaFromYAML drafts a draft-07 JSON Schema (as YAML) from an existing
+// values file: mappings become objects, sequences arrays (item schemas merged
+// across elements), scalars get their YAML type, comments above or beside a
+// key become its "description", and current values become "default"s so that
+// gen-sample reproduces the input.
+func InferSchemaFromYAML(valuesPath string) ([]byte, error) {
+	raw, err := os.ReadFile(valuesPath)
+	if err != nil {
+		return nil, fmt.Errorf("read values: %w", err)
+	}
+	node, err := inferSchemaNode(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := y3.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&y3.Node{Kind: y3.DocumentNode, Content: []*y3.Node{node}}); err != nil {
+		return nil, fmt.Errorf("yaml encode: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// inferSchemaNode drafts a schema mapping node (with "$schema") from YAML bytes.
+func inferSchemaNode(raw []byte) (*y3.Node, error) {
+	var doc y3.Node
+	if err := y3.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("parse values: %w", err)
+	}
+
+	root := &inferred{}
+	if doc.Kind == y3.DocumentNode && len(doc.Content) > 0 {
+		root.add(doc.Content[0])
+	}
+
+	node := root.toNode(false)
+	node.Content = append([]*y3.Node{
+		yamlKey("$schema"), valueToYAMLNode("http://json-schema.org/draft-07/schema#"),
+	}, node.Content...)
+	return node, nil
+}
+
+// inferred accumulates the shape of one or more YAML values at the same position.
+type inferred struct {
+	types       []string // JSON Schema types in first-seen order
+	description string
+	def         *y3.Node // first value seen, used as "default" for leaves and arrays
+
+	keys  []string // property order
+	props map[string]*inferred
+	items *inferred
+}
+
+func (in *inferred) add(n *y3.Node) {
+	for n.Kind == y3.AliasNode {
+		n = n.Alias
+	}
+	if in.def == nil {
+		in.def = n
+	}
+	switch n.Kind {
+	case y3.MappingNode:
+		in.addType("object")
+		if in.props == nil {
+			in.props = map[string]*inferred{}
+		}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k, v := n.Content[i], n.Content[i+1]
+			if k.Value == "<<" {
+				continue // merge keys are expanded by YAML readers; skip in the schema
+			}
+			p, ok := in.props[k.Value]
+			if !ok {
+				p = &inferred{}
+				in.props[k.Value] = p
+				in.keys = append(in.keys, k.Value)
+			}
+			if p.description == "" {
+				p.description = commentText(k.HeadComment, k.LineComment, v.LineComment)
+			}
+			p.add(v)
+		}
+	case y3.SequenceNode:
+		in.addType("array")
+		if in.items == nil {
+			in.items = &inferred{}
+		}
+		for _, e := range n.Content {
+			in.items.add(e)
+		}
+	case y3.ScalarNode:
+		switch n.ShortTag() {
+		case "!!int":
+			in.addType("integer")
+		case "!!float":
+			in.addType("number")
+		case "!!bool":
+			in.addType("boolean")
+		case "!!null":
+			// null carries no type information; leave the type open
+		default:
+			in.addType("string")
+		}
+	}
+}
+
+func (in *inferred) addType(t string) {
+	for _, have := range in.types {
+		if have == t {
+			return
+		}
+	}
+	in.types = append(in.types, t)
+}
+
+// toNode renders the accumulated shape as a schema mapping node. Defaults are
+// omitted inside array items, where a single default is meaningless.
+func (in *inferred) toNode(inItems bool) *y3.Node {
+	node := &y3.Node{Kind: y3.MappingNode}
+	if in.description != "" {
+		node.Content = append(node.Content, yamlKey("description"), valueToYAMLNode(in.description))
+	}
+	switch len(in.types) {
+	case 0:
+	case 1:
+		node.Content = append(node.Content, yamlKey("type"), valueToYAMLNode(in.types[0]))
+	default:
+		seq := &y3.Node{Kind: y3.SequenceNode, Style: y3.FlowStyle}
+		for _, t := range in.types {
+			seq.Content = append(seq.Content, valueToYAMLNode(t))
+		}
+		node.Content = append(node.Content, yamlKey("type"), seq)
+	}
+
+	if len(in.keys) > 0 {
+		props := &y3.Node{Kind: y3.MappingNode}
+		for _, k := range in.keys {
+			props.Content = append(props.Content, yamlKey(k), in.props[k].toNode(inItems))
+		}
+		node.Content = append(node.Content, yamlKey("properties"), props)
+	}
+	if in.items != nil && in.items.def != nil {
+		node.Content = append(node.Content, yamlKey("items"), in.items.toNode(true))
+	}
+
+	// Objects get their defaults from their properties.
+	if !inItems && in.def != nil && in.def.Kind != y3.MappingNode {
+		node.Content = append(node.Content, yamlKey("default"), stripComments(in.def))
+	}
+	return node
+}
+
+// commentText joins YAML comments into a one-line description, dropping "#"
+// markers and helm-docs style "--" prefixes.
+func commentText(comments ...string) string {
+	var parts []string
+	for _, c := range comments {
+		for _, line := range strings.Split(c, "\n") {
+			line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "#"))
+			line = strings.TrimSpace(strings.TrimPrefix(line, "--"))
+			if line != "" {
+				parts = append(parts, line)
+			}
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// stripComments deep-copies a node without its comments.
+func stripComments(n *y3.Node) *y3.Node {
+	for n.Kind == y3.AliasNode {
+		n = n.Alias
+	}
+	out := &y3.Node{Kind: n.Kind, Style: n.Style, Tag: n.Tag, Value: n.Value}
+	for _, c := range n.Content {
+		out.Content = append(out.Content, stripComments(c))
+	}
+	return out
+}
+
+func yamlKey(k string) *y3.Node {
+	return &y3.Node{Kind: y3.ScalarNode, Tag: "!!str", Value: k}
+}
@@ -0,0 +1,149 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/yaml"
+)
+
+func TestInferSchemaFromYAML(t *testing.T) {
+	const header = "$schema: http://json-schema.org/draft-07/schema#\n"
+	tests := []struct {
+		name   string
+		values string
+		want   string
+	}{
+		{
+			name:   "empty file",
+			values: "",
+			want:   header,
+		},
+		{
+			name:   "scalars with comments",
+			values: "# -- Application name\nname: api\nreplicas: 2 # how many\nratio: 0.5\ndebug: false\n",
+			want: header + `type: object
+properties:
+  name:
+    description: Application name
+    type: string
+    default: api
+  replicas:
+    description: how many
+    type: integer
+    default: 2
+  ratio:
+    type: number
+    default: 0.5
+  debug:
+    type: boolean
+    default: false
+`,
+		},
+		{
+			name:   "array items merged across elements",
+			values: "items:\n  - id: 1\n  - id: two\n    label: x\n",
+			want: header + `type: object
+properties:
+  items:
+    type: array
+    items:
+      type: object
+      properties:
+        id:
+          type: [integer, string]
+        label:
+          type: string
+    default:
+      - id: 1
+      - id: two
+        label: x
+`,
+		},
+		{
+			name:   "null leaves the type open",
+			values: "empty: null\n",
+			want:   header + "type: object\nproperties:\n  empty:\n    default: null\n",
+		},
+		{
+			name:   "anchors and merge keys",
+			values: "base: &b\n  port: 80\nsvc:\n  <<: *b\n  name: web\n",
+			want: header + `type: object
+properties:
+  base:
+    type: object
+    properties:
+      port:
+        type: integer
+        default: 80
+  svc:
+    type: object
+    properties:
+      name:
+        type: string
+        default: web
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := filepath.Join(t.TempDir(), "values.yaml")
+			if err := os.WriteFile(p, []byte(tt.values), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := InferSchemaFromYAML(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("schema:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+// An inferred schema validates its input and gen-sample reproduces it.
+func TestInferSchemaRoundTrip(t *testing.T) {
+	values := `name: api
+replicas: 2
+image:
+  repository: nginx
+  tag: "1.27"
+hosts: [a, b]
+ports:
+  - {name: http, port: 80}
+`
+	dir := t.TempDir()
+	valuesPath := filepath.Join(dir, "values.yaml")
+	schemaPath := filepath.Join(dir, "config.schema.yaml")
+	if err := os.WriteFile(valuesPath, []byte(values), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sch, err := InferSchemaFromYAML(valuesPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(schemaPath, sch, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateYAMLWithSchema(schemaPath, valuesPath); err != nil {
+		t.Fatalf("input does not validate against the inferred schema: %v", err)
+	}
+	sample, err := BuildSampleFromSchema(schemaPath, SampleOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want, got any
+	if err := yaml.Unmarshal([]byte(values), &want); err != nil {
+		t.Fatal(err)
+	}
+	if err := yaml.Unmarshal(sample, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sample:\n%s\nwant the values back:\n%s", sample, strings.TrimSpace(values))
+	}
+}
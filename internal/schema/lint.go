@@ -0,0 +1,376 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/besrabasant/valuesctl/internal/celx"
+	y3 "gopkg.in/yaml.v3"
+	"sigs.k8s.io/yaml"
+)
+
+// LintIssue is a schema authoring mistake found by LintSchema.
+type LintIssue struct {
+	Line    int
+	Column  int
+	Pointer string // JSON pointer of the offending schema node
+	Message string
+}
+
+func (i LintIssue) String() string {
+	ptr := i.Pointer
+	if ptr == "" {
+		ptr = "/"
+	}
+	return fmt.Sprintf("%d:%d: %s: %s", i.Line, i.Column, ptr, i.Message)
+}
+
+// draft-07 keywords plus "deprecated"; any "x-" keyword is accepted as an extension.
+var knownKeywords = map[string]bool{
+	"$schema": true, "$id": true, "$ref": true, "$comment": true, "$defs": true, "definitions": true,
+	"title": true, "description": true, "default": true, "examples": true, "readOnly": true, "writeOnly": true, "deprecated": true,
+	"type": true, "enum": true, "const": true, "format": true, "contentMediaType": true, "contentEncoding": true,
+	"multipleOf": true, "maximum": true, "exclusiveMaximum": true, "minimum": true, "exclusiveMinimum": true,
+	"maxLength": true, "minLength": true, "pattern": true,
+	"items": true, "additionalItems": true, "maxItems": true, "minItems": true, "uniqueItems": true, "contains": true,
+	"maxProperties": true, "minProperties": true, "required": true, "properties": true, "patternProperties": true,
+	"additionalProperties": true, "dependencies": true, "propertyNames": true,
+	"if": true, "then": true, "else": true, "allOf": true, "anyOf": true, "oneOf": true, "not": true,
+}
+
+var jsonTypes = map[string]bool{
+	"null": true, "boolean": true, "object": true, "array": true, "number": true, "integer": true, "string": true,
+}
+
+// keywords that only constrain values of one type
+var typeKeywords = map[string]string{
+	"properties": "object", "patternProperties": "object", "additionalProperties": "object", "required": "object",
+	"minProperties": "object", "maxProperties": "object", "propertyNames": "object", "dependencies": "object",
+	"items": "array", "additionalItems": "array", "minItems": "array", "maxItems": "array", "uniqueItems": "array", "contains": "array",
+	"minLength": "string", "maxLength": "string", "pattern": "string", "format": "string",
+	"minimum": "number", "maximum": "number", "exclusiveMinimum": "number", "exclusiveMaximum": "number", "multipleOf": "number",
+}
+
+// LintSchema checks a schema (YAML or JSON) for authoring mistakes: malformed
+// subschemas (e.g. a property written as a list), unknown keywords, defaults,
+// consts, enum members or examples that violate their own schema, "required"
+// entries missing from "properties" and unreachable branches or keywords.
+// Issues carry the line/column in the schema file.
+func LintSchema(schemaPath string) ([]LintIssue, error) {
+	raw, err := os.ReadFile(schemaPath)
+	if err != nil {
+		return nil, fmt.Errorf("read schema: %w", err)
+	}
+	var doc y3.Node
+	if err := y3.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("parse schema: %w", err)
+	}
+	if doc.Kind != y3.DocumentNode || len(doc.Content) == 0 {
+		return nil, fmt.Errorf("parse schema: empty document")
+	}
+
+	l := &linter{}
+	root, err := nodeToJSONValue(doc.Content[0])
+	if err != nil {
+		return nil, err
+	}
+	l.root, _ = root.(map[string]any)
+	if err := registerSchemaFormats(root); err != nil {
+		l.report(keyNode(doc.Content[0], "x-formats"), "/x-formats", "%v", err)
+	}
+	l.walk(doc.Content[0], "")
+
+	sort.SliceStable(l.issues, func(i, j int) bool {
+		if l.issues[i].Line != l.issues[j].Line {
+			return l.issues[i].Line < l.issues[j].Line
+		}
+		return l.issues[i].Column < l.issues[j].Column
+	})
+	return l.issues, nil
+}
+
+type linter struct {
+	root   map[string]any
+	issues []LintIssue
+}
+
+func (l *linter) report(n *y3.Node, ptr, format string, args ...any) {
+	l.issues = append(l.issues, LintIssue{Line: n.Line, Column: n.Column, Pointer: ptr, Message: fmt.Sprintf(format, args...)})
+}
+
+// walk lints the subschema n located at JSON pointer ptr.
+func (l *linter) walk(n *y3.Node, ptr string) {
+	n = resolveAlias(n)
+	if n.Kind == y3.ScalarNode && n.ShortTag() == "!!bool" {
+		return // boolean schema
+	}
+	if n.Kind != y3.MappingNode {
+		l.report(n, ptr, "schema must be a mapping, got %s", nodeKindName(n))
+		return
+	}
+
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], resolveAlias(n.Content[i+1])
+		kptr := ptr + "/" + escapePointer(k.Value)
+
+		switch k.Value {
+		case "additionalProperties", "additionalItems", "not", "if", "then", "else", "contains", "propertyNames":
+			l.walk(v, kptr)
+		case "properties", "patternProperties", "definitions", "$defs":
+			if v.Kind != y3.MappingNode {
+				l.report(v, kptr, "%s must be a mapping of schemas, got %s", k.Value, nodeKindName(v))
+				continue
+			}
+			for j := 0; j+1 < len(v.Content); j += 2 {
+				l.walk(v.Content[j+1], kptr+"/"+escapePointer(v.Content[j].Value))
+			}
+		case "items":
+			if v.Kind == y3.SequenceNode {
+				for j, e := range v.Content {
+					l.walk(e, fmt.Sprintf("%s/%d", kptr, j))
+				}
+			} else {
+				l.walk(v, kptr)
+			}
+		case "allOf", "anyOf", "oneOf":
+			if v.Kind != y3.SequenceNode || len(v.Content) == 0 {
+				l.report(v, kptr, "%s must be a non-empty list of schemas", k.Value)
+				continue
+			}
+			for j, e := range v.Content {
+				l.walk(e, fmt.Sprintf("%s/%d", kptr, j))
+			}
+		case "type":
+			l.checkTypeKeyword(v, kptr)
+		case "required", "enum":
+			if v.Kind != y3.SequenceNode {
+				l.report(v, kptr, "%s must be a list, got %s", k.Value, nodeKindName(v))
+			}
+		default:
+			if !knownKeywords[k.Value] && !strings.HasPrefix(k.Value, "x-") {
+				msg := fmt.Sprintf("unknown keyword %q", k.Value)
+				if s := closestKeyword(k.Value); s != "" {
+					msg += fmt.Sprintf(" (did you mean %q?)", s)
+				}
+				l.report(k, kptr, "%s", msg)
+			}
+		}
+	}
+
+	val, err := nodeToJSONValue(n)
+	if err != nil {
+		return
+	}
+	if m, ok := val.(map[string]any); ok {
+		l.checkSemantics(n, ptr, m)
+	}
+}
+
+func (l *linter) checkTypeKeyword(v *y3.Node, ptr string) {
+	var names []*y3.Node
+	switch v.Kind {
+	case y3.ScalarNode:
+		names = []*y3.Node{v}
+	case y3.SequenceNode:
+		names = v.Content
+	default:
+		l.report(v, ptr, "type must be a string or list of strings")
+		return
+	}
+	for _, t := range names {
+		if !jsonTypes[t.Value] {
+			l.report(t, ptr, "unknown type %q", t.Value)
+		}
+	}
+}
+
+// checkSemantics runs the checks that need the decoded subschema.
+func (l *linter) checkSemantics(n *y3.Node, ptr string, m map[string]any) {
+	types := schemaTypes(m)
+
+	// required entries must be declared when properties are listed
+	if props, ok := m["properties"].(map[string]any); ok {
+		if _, hasPattern := m["patternProperties"]; !hasPattern {
+			for _, r := range stringList(m["required"]) {
+				if _, ok := props[r]; !ok {
+					l.report(keyNode(n, "required"), ptr+"/required", "required property %q is not defined in properties", r)
+				}
+			}
+		}
+	}
+
+	// enum members must match the declared type
+	enum, _ := m["enum"].([]any)
+	wrongType := map[string]bool{}
+	for i, e := range enum {
+		if !typeAllows(types, jsonType(e)) {
+			p := fmt.Sprintf("%s/enum/%d", ptr, i)
+			wrongType[p] = true
+			l.report(keyNode(n, "enum"), p, "enum value %s is %s, but type is %s", formatValue(e), jsonType(e), strings.Join(types, "|"))
+		}
+	}
+
+	// default, const, the remaining enum members and examples must satisfy
+	// their own schema
+	for _, ve := range subschemaValueErrors(l.root, ptr, m) {
+		if wrongType[ve.Pointer] {
+			continue
+		}
+		kw := strings.Split(strings.TrimPrefix(ve.Pointer, ptr+"/"), "/")[0]
+		l.report(keyNode(n, kw), ve.Pointer, "%s %s violates its schema: %v", kw, formatValue(ve.Value), ve.Err)
+	}
+
+	// x-rules must be well-formed, compiling CEL expressions
+	rules, err := schemaRules(m)
+	if err != nil {
+		l.report(keyNode(n, "x-rules"), ptr+"/x-rules", "%v", err)
+	}
+	for i, r := range rules {
+		if _, err := celx.Compile(r.Expr, "self"); err != nil {
+			l.report(keyNode(n, "x-rules"), fmt.Sprintf("%s/x-rules/%d", ptr, i), "%v", err)
+		}
+	}
+
+	// keywords for types the schema can never hold have no effect
+	if len(types) > 0 {
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k := n.Content[i]
+			if want, ok := typeKeywords[k.Value]; ok && !typeAllows(types, want) && !(want == "number" && typeAllows(types, "integer")) {
+				l.report(k, ptr+"/"+escapePointer(k.Value), "%s is unreachable: type is %s, not %s", k.Value, strings.Join(types, "|"), want)
+			}
+		}
+	}
+
+	// branches whose type can never match the parent's type
+	for _, kw := range []string{"allOf", "anyOf", "oneOf"} {
+		branches, _ := m[kw].([]any)
+		for i, b := range branches {
+			bm, _ := b.(map[string]any)
+			bt := schemaTypes(bm)
+			if len(types) > 0 && len(bt) > 0 && !typesOverlap(types, bt) {
+				l.report(keyNode(n, kw), fmt.Sprintf("%s/%s/%d", ptr, kw, i), "%s branch %d is unreachable: type %s never matches %s", kw, i, strings.Join(bt, "|"), strings.Join(types, "|"))
+			}
+		}
+	}
+
+	// then/else without if are never evaluated
+	if _, hasIf := m["if"]; !hasIf {
+		for _, kw := range []string{"then", "else"} {
+			if _, ok := m[kw]; ok {
+				l.report(keyNode(n, kw), ptr+"/"+kw, "%s is unreachable without if", kw)
+			}
+		}
+	}
+}
+
+// typesOverlap reports whether some value could satisfy both type lists.
+func typesOverlap(a, b []string) bool {
+	for _, t := range a {
+		if typeAllows(b, t) {
+			return true
+		}
+	}
+	for _, t := range b {
+		if typeAllows(a, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// closestKeyword suggests a known keyword within edit distance 2.
+func closestKeyword(k string) string {
+	best, bestDist := "", 3
+	for kw := range knownKeywords {
+		if d := levenshtein(strings.ToLower(k), strings.ToLower(kw)); d < bestDist || (d == bestDist && kw < best) {
+			best, bestDist = kw, d
+		}
+	}
+	return best
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+// nodeToJSONValue decodes a YAML node the way the rest of the package reads
+// schemas (YAML -> JSON), so timestamps stay strings and numbers are float64.
+func nodeToJSONValue(n *y3.Node) (any, error) {
+	b, err := y3.Marshal(n)
+	if err != nil {
+		return nil, err
+	}
+	j, err := yaml.YAMLToJSON(b)
+	if err != nil {
+		return nil, err
+	}
+	var v any
+	if err := json.Unmarshal(j, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// keyNode returns the key node for k in mapping n (for positions), or n itself.
+func keyNode(n *y3.Node, k string) *y3.Node {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == k {
+			return n.Content[i]
+		}
+	}
+	return n
+}
+
+func resolveAlias(n *y3.Node) *y3.Node {
+	for n.Kind == y3.AliasNode && n.Alias != nil {
+		n = n.Alias
+	}
+	return n
+}
+
+func nodeKindName(n *y3.Node) string {
+	switch n.Kind {
+	case y3.MappingNode:
+		return "mapping"
+	case y3.SequenceNode:
+		return "list"
+	case y3.ScalarNode:
+		return "scalar " + formatValue(n.Value)
+	}
+	return "unknown node"
+}
+
+func escapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
+
+func stringList(v any) []string {
+	arr, _ := v.([]any)
+	out := make([]string, 0, len(arr))
+	for _, e := range arr {
+		if s, ok := e.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,93 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLintSchema(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		want   []string
+	}{
+		{
+			name:   "clean schema",
+			schema: "type: object\nrequired: [name]\nproperties:\n  name: {type: string, default: api}\n",
+		},
+		{
+			name:   "property written as a list",
+			schema: "type: object\nproperties:\n  name:\n    - type: string\n",
+			want:   []string{"4:5: /properties/name: schema must be a mapping, got list"},
+		},
+		{
+			name:   "unknown keyword and type",
+			schema: "properties:\n  tag:\n    type: strng\n    patern: \"^v\"\n",
+			want: []string{
+				`3:11: /properties/tag/type: unknown type "strng"`,
+				`4:5: /properties/tag/patern: unknown keyword "patern" (did you mean "pattern"?)`,
+			},
+		},
+		{
+			name:   "extension keywords are allowed",
+			schema: "type: string\nx-example: v1\n",
+		},
+		{
+			name:   "default violating its schema",
+			schema: "properties:\n  port:\n    type: integer\n    default: http\n",
+			want:   []string{`4:5: /properties/port/default: default "http" violates its schema: (root): Invalid type. Expected: integer, given: string`},
+		},
+		{
+			name:   "enum members of the wrong type or failing the schema",
+			schema: "type: string\npattern: \"^[a-z]+$\"\nenum: [a, 5, B]\n",
+			want: []string{
+				"3:1: /enum/1: enum value 5 is integer, but type is string",
+				`3:1: /enum/2: enum "B" violates its schema: (root): Does not match pattern '^[a-z]+$'`,
+			},
+		},
+		{
+			name:   "required entry not in properties",
+			schema: "type: object\nrequired: [name, missing]\nproperties:\n  name: {type: string}\n",
+			want:   []string{`2:1: /required: required property "missing" is not defined in properties`},
+		},
+		{
+			name:   "unreachable keyword",
+			schema: "type: integer\nminLength: 2\n",
+			want:   []string{"2:1: /minLength: minLength is unreachable: type is integer, not string"},
+		},
+		{
+			name:   "unreachable branches",
+			schema: "type: string\nthen: {type: string}\nanyOf:\n  - {type: integer}\n  - {minLength: 1}\n",
+			want: []string{
+				"2:1: /then: then is unreachable without if",
+				"3:1: /anyOf/0: anyOf branch 0 is unreachable: type integer never matches string",
+			},
+		},
+		{
+			name:   "empty composition",
+			schema: "oneOf: []\n",
+			want:   []string{"1:8: /oneOf: oneOf must be a non-empty list of schemas"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := filepath.Join(t.TempDir(), "schema.yaml")
+			if err := os.WriteFile(p, []byte(tt.schema), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			issues, err := LintSchema(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, i := range issues {
+				got = append(got, i.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("issues:\n%q\nwant:\n%q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package schema
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	y3 "gopkg.in/yaml.v3"
+)
+
+// MergeResult is the outcome of MergeSampleInto.
+type MergeResult struct {
+	Output  []byte
+	Added   []string // dotted paths of keys added from the schema
+	Removed []string // dotted paths of config keys the schema does not declare
+}
+
+// MergeSampleInto adds the schema properties missing from an existing config,
+// as gen-sample would render them (descriptions and defaults, honoring
+// opts.Mode), while keeping the config's own values, comments and key order.
+// New keys are appended after the existing ones of their mapping. Keys the
+// schema does not declare are reported in Removed and, if markRemoved is set,
+// get a "# not in schema" comment.
+func MergeSampleInto(schemaPath, cfgPath string, opts SampleOptions, markRemoved bool) (*MergeResult, error) {
+	sch, err := loadSchema(schemaPath)
+	if err != nil {
+		return nil, err
+	}
+	root, _ := sch.(map[string]any)
+	if root == nil {
+		return nil, fmt.Errorf("schema root must be an object")
+	}
+	opts.root = root
+
+	raw, err := os.ReadFile(cfgPath)
+	if err != nil {
+		return nil, err
+	}
+	var doc y3.Node
+	if err := y3.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", cfgPath, err)
+	}
+	if doc.Kind == 0 {
+		// empty, or only comments (which the parser drops): keep them as the
+		// document's head comment
+		doc = y3.Node{Kind: y3.DocumentNode, HeadComment: strings.TrimSpace(string(raw)), Content: []*y3.Node{{Kind: y3.MappingNode}}}
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != y3.MappingNode {
+		return nil, fmt.Errorf("%s: top level must be a mapping", cfgPath)
+	}
+
+	res := &MergeResult{}
+	mg := &sampleMerger{opts: opts, markRemoved: markRemoved, res: res}
+	mg.merge(root, doc.Content[0], "")
+
+	var buf bytes.Buffer
+	enc := y3.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("yaml encode: %w", err)
+	}
+	res.Output = buf.Bytes()
+	return res, nil
+}
+
+type sampleMerger struct {
+	opts        SampleOptions
+	markRemoved bool
+	res         *MergeResult
+}
+
+// merge walks cfg (a node of the user's config) alongside schema s.
+func (mg *sampleMerger) merge(s map[string]any, cfg *y3.Node, path string) {
+	m := mg.opts.resolve(s)
+	switch cfg.Kind {
+	case y3.MappingNode:
+		if schemaKind(m) != "object" && len(alternatives(m)) == 0 {
+			return
+		}
+		mg.mergeMapping(m, cfg, path)
+	case y3.SequenceNode:
+		items, ok := m["items"].(map[string]any)
+		if !ok {
+			return
+		}
+		for i, e := range cfg.Content {
+			mg.merge(items, e, fmt.Sprintf("%s[%d]", path, i))
+		}
+	}
+}
+
+func (mg *sampleMerger) mergeMapping(m map[string]any, cfg *y3.Node, path string) {
+	props, _ := m["properties"].(map[string]any)
+
+	// oneOf/anyOf branch properties are known (not "removed") but not added,
+	// since which branch applies is the user's choice
+	known := map[string]bool{}
+	for k := range props {
+		known[k] = true
+	}
+	for _, b := range alternatives(m) {
+		if bm, ok := b.(map[string]any); ok {
+			bp, _ := mg.opts.resolve(bm)["properties"].(map[string]any)
+			for k := range bp {
+				known[k] = true
+			}
+		}
+	}
+
+	present := map[string]bool{}
+	for i := 0; i+1 < len(cfg.Content); i += 2 {
+		keyNode, valNode := cfg.Content[i], cfg.Content[i+1]
+		k := keyNode.Value
+		present[k] = true
+		p := joinPath(path, k)
+		if sub, ok := props[k].(map[string]any); ok {
+			mg.merge(sub, valNode, p)
+			continue
+		}
+		if known[k] {
+			continue
+		}
+		if aps, ok := m["additionalProperties"].(map[string]any); ok {
+			mg.merge(aps, valNode, p)
+			continue
+		}
+		if allowed, ok := m["additionalProperties"].(bool); (ok && allowed) || len(props) == 0 {
+			// explicitly open object or free-form map
+			continue
+		}
+		mg.res.Removed = append(mg.res.Removed, p)
+		switch {
+		case !mg.markRemoved || strings.Contains(keyNode.LineComment, "not in schema"):
+		case keyNode.LineComment == "":
+			keyNode.LineComment = "# not in schema"
+		default:
+			keyNode.LineComment += "; not in schema"
+		}
+	}
+
+	required := stringSet(m["required"])
+	var pending []string
+	for _, k := range sortedKeys(props) {
+		sub, _ := props[k].(map[string]any)
+		if present[k] || sub == nil {
+			continue
+		}
+		switch {
+		case !required[k] && mg.opts.Mode == "minimal":
+			continue
+		case !required[k] && mg.opts.Mode == "annotated":
+			// skip keys already commented out by an earlier merge
+			if !hasCommentLine(cfg, "# "+k+":") {
+				pending = append(pending, commentedOutKey(k, sub, mg.opts))
+			}
+			continue
+		}
+		keyNode, valNode := samplePair(k, sub, required[k], mg.opts)
+		cfg.Content = append(cfg.Content, keyNode, valNode)
+		mg.res.Added = append(mg.res.Added, joinPath(path, k))
+	}
+	if len(pending) > 0 || len(cfg.Content) > 2*len(present) {
+		// added keys and comments need block style
+		cfg.Style &^= y3.FlowStyle
+	}
+	if len(pending) > 0 {
+		if n := len(cfg.Content); n > 0 {
+			cfg.Content[n-2].FootComment = joinComments(cfg.Content[n-2].FootComment, strings.Join(pending, "\n"))
+		} else {
+			cfg.HeadComment = joinComments(cfg.HeadComment, strings.Join(pending, "\n"))
+		}
+	}
+}
+
+// hasCommentLine reports whether a comment of mapping n or of its keys and
+// values has a line starting with prefix.
+func hasCommentLine(n *y3.Node, prefix string) bool {
+	texts := []string{n.HeadComment, n.FootComment}
+	for _, c := range n.Content {
+		texts = append(texts, c.HeadComment, c.FootComment)
+	}
+	for _, t := range texts {
+		for _, l := range strings.Split(t, "\n") {
+			if strings.HasPrefix(strings.TrimSpace(l), prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMergeSampleInto(t *testing.T) {
+	const schema = `type: object
+required: [name]
+properties:
+  name: {type: string, description: App name, default: api}
+  replicas: {type: integer, default: 2, description: Replica count}
+  image:
+    type: object
+    properties:
+      repository: {type: string, default: nginx}
+      tag: {type: string, default: latest}
+`
+	tests := []struct {
+		name        string
+		cfg         string
+		opts        SampleOptions
+		markRemoved bool
+		want        string
+		added       []string
+		removed     []string
+	}{
+		{
+			name:        "missing keys appended, values, comments and order kept",
+			cfg:         "# my config\nreplicas: 5 # keep\nimage:\n  tag: v1\nold: x\n",
+			markRemoved: true,
+			want:        "# my config\nreplicas: 5 # keep\nimage:\n  tag: v1\n  repository: nginx\nold: x # not in schema\n# App name\nname: api\n",
+			added:       []string{"image.repository", "name"},
+			removed:     []string{"old"},
+		},
+		{
+			name:    "removed keys only reported without marking",
+			cfg:     "name: web\nreplicas: 1\nimage: {repository: r, tag: t}\nold: x\n",
+			want:    "name: web\nreplicas: 1\nimage: {repository: r, tag: t}\nold: x\n",
+			removed: []string{"old"},
+		},
+		{
+			name:  "minimal mode adds only required keys",
+			cfg:   "replicas: 1\n",
+			opts:  SampleOptions{Mode: "minimal"},
+			want:  "replicas: 1\n# App name\nname: api\n",
+			added: []string{"name"},
+		},
+		{
+			name:  "empty config",
+			cfg:   "",
+			want:  "image:\n  repository: nginx\n  tag: latest\n# App name\nname: api\n# Replica count\nreplicas: 2\n",
+			added: []string{"image", "name", "replicas"},
+		},
+		{
+			name:  "comment-only config keeps its comments",
+			cfg:   "# team config\n# owned by ops\n",
+			opts:  SampleOptions{Mode: "minimal"},
+			want:  "# team config\n# owned by ops\n\n# App name\nname: api\n",
+			added: []string{"name"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			schemaPath := filepath.Join(dir, "config.schema.yaml")
+			cfgPath := filepath.Join(dir, "config.yaml")
+			if err := os.WriteFile(schemaPath, []byte(schema), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(cfgPath, []byte(tt.cfg), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			res, err := MergeSampleInto(schemaPath, cfgPath, tt.opts, tt.markRemoved)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(res.Output) != tt.want {
+				t.Errorf("output:\n%s\nwant:\n%s", res.Output, tt.want)
+			}
+			if !reflect.DeepEqual(res.Added, tt.added) {
+				t.Errorf("added = %v, want %v", res.Added, tt.added)
+			}
+			if !reflect.DeepEqual(res.Removed, tt.removed) {
+				t.Errorf("removed = %v, want %v", res.Removed, tt.removed)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// typeNode is the language-neutral shape of a schema used by the code
+// generators. Objects with properties and string enums become named types.
+type typeNode struct {
+	Kind        string // object, map, array, string, integer, number, boolean, any
+	Name        string // type name for objects and enums
+	Description string
+	Fields      []typeField // objects
+	Elem        *typeNode   // arrays and maps
+	Enum        []any
+	Default     any
+	HasDefault  bool
+	Nullable    bool // "null" is one of the allowed types
+}
+
+// typeField is a property of an object typeNode.
+type typeField struct {
+	Key      string // property name in the config
+	Required bool
+	Type     *typeNode
+}
+
+// typeModel builds typeNodes for a schema, naming nested objects after their
+// path (app.flags -> AppFlags) and $ref targets after their definition.
+type typeModel struct {
+	root  map[string]any
+	named []*typeNode          // named types in declaration order
+	refs  map[string]*typeNode // $ref -> type, so shared definitions are emitted once
+	names map[string]bool
+}
+
+func newTypeModel(root map[string]any) *typeModel {
+	return &typeModel{root: root, refs: map[string]*typeNode{}, names: map[string]bool{}}
+}
+
+// build converts schema m into a typeNode; name is the suggested type name.
+func (tm *typeModel) build(m map[string]any, name string) *typeNode {
+	if ref, ok := m["$ref"].(string); ok {
+		// a $ref with sibling keywords (e.g. its own default) is a variant of
+		// the definition, so only bare references share one type
+		bare := len(m) == 1
+		if t, ok := tm.refs[ref]; ok && bare {
+			return t
+		}
+		refName := pascalName(ref[strings.LastIndex(ref, "/")+1:])
+		resolved := resolveRef(tm.root, m)
+		if _, still := resolved["$ref"]; !still && !bare {
+			return tm.build(resolved, refName)
+		} else if !still {
+			// register before recursing so self-referencing definitions terminate
+			placeholder := &typeNode{Kind: "any"}
+			tm.refs[ref] = placeholder
+			t := tm.build(resolved, refName)
+			*placeholder = *t
+			return placeholder
+		}
+	}
+
+	m = mergeAllOf(tm.root, m)
+	t := &typeNode{Description: strings.TrimSpace(stringOr(m["description"], stringOr(m["title"], "")))}
+	t.Default, t.HasDefault = m["default"]
+	for _, ty := range schemaTypes(m) {
+		if ty == "null" {
+			t.Nullable = true
+		}
+	}
+
+	switch schemaKind(m) {
+	case "object":
+		props, _ := m["properties"].(map[string]any)
+		if len(props) == 0 {
+			t.Kind = "map"
+			if aps, ok := m["additionalProperties"].(map[string]any); ok {
+				t.Elem = tm.build(aps, name+"Value")
+			} else {
+				t.Elem = &typeNode{Kind: "any"}
+			}
+			return t
+		}
+		t.Kind = "object"
+		t.Name = tm.uniqueName(name)
+		tm.named = append(tm.named, t)
+		required := stringSet(m["required"])
+		for _, k := range sortedKeys(props) {
+			sub, ok := props[k].(map[string]any)
+			if !ok {
+				continue
+			}
+			t.Fields = append(t.Fields, typeField{Key: k, Required: required[k], Type: tm.build(sub, name+pascalName(k))})
+		}
+	case "array":
+		t.Kind = "array"
+		if items, ok := m["items"].(map[string]any); ok {
+			t.Elem = tm.build(items, name+"Item")
+		} else {
+			t.Elem = &typeNode{Kind: "any"}
+		}
+	case "string", "integer", "number", "boolean":
+		t.Kind = schemaKind(m)
+		if enum, ok := m["enum"].([]any); ok && len(enum) > 0 && t.Kind == "string" {
+			t.Enum = enum
+			t.Name = tm.uniqueName(name)
+			tm.named = append(tm.named, t)
+		} else if ok {
+			t.Enum = enum
+		}
+	default:
+		// no type, unions (oneOf/anyOf) or null: nothing sensible to narrow to
+		t.Kind = "any"
+	}
+	return t
+}
+
+func (tm *typeModel) uniqueName(name string) string {
+	if name == "" {
+		name = "Config"
+	}
+	out := name
+	for i := 2; tm.names[out]; i++ {
+		out = fmt.Sprintf("%s%d", name, i)
+	}
+	tm.names[out] = true
+	return out
+}
+
+// pascalName turns "tls-secret_name" or "tlsSecretName" into "TLSSecretName",
+// upper-casing common initialisms.
+func pascalName(s string) string {
+	var words []string
+	var cur []rune
+	flush := func() {
+		if len(cur) > 0 {
+			words = append(words, string(cur))
+			cur = nil
+		}
+	}
+	rs := []rune(s)
+	for i, r := range rs {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && i > 0 && (unicode.IsLower(rs[i-1]) || (i+1 < len(rs) && unicode.IsLower(rs[i+1]) && unicode.IsUpper(rs[i-1]))):
+			flush()
+			cur = append(cur, r)
+		default:
+			cur = append(cur, r)
+		}
+	}
+	flush()
+
+	var b strings.Builder
+	for _, w := range words {
+		if up := strings.ToUpper(w); commonInitialisms[up] {
+			b.WriteString(up)
+			continue
+		}
+		r := []rune(w)
+		b.WriteString(strings.ToUpper(string(r[0])) + string(r[1:]))
+	}
+	out := b.String()
+	if out == "" || unicode.IsDigit([]rune(out)[0]) {
+		out = "X" + out
+	}
+	return out
+}
+
+var commonInitialisms = map[string]bool{
+	"API": true, "CPU": true, "CSS": true, "DNS": true, "GCS": true, "HTML": true, "HTTP": true, "HTTPS": true,
+	"ID": true, "IP": true, "JSON": true, "OIDC": true, "S3": true, "SQL": true, "SSH": true, "TCP": true,
+	"TLS": true, "TTL": true, "UDP": true, "UI": true, "URI": true, "URL": true, "UUID": true, "YAML": true,
+}
+
+func stringOr(v any, def string) string {
+	if s, ok := v.(string); ok && s != "" {
+		return s
+	}
+	return def
+}
+
+// sortedNamed returns named types in a stable order: root first, then by name.
+func (tm *typeModel) sortedNamed() []*typeNode {
+	out := append([]*typeNode(nil), tm.named...)
+	if len(out) > 1 {
+		rest := out[1:]
+		sort.SliceStable(rest, func(i, j int) bool { return rest[i].Name < rest[j].Name })
+	}
+	return out
+}
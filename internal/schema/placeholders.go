@@ -0,0 +1,71 @@
+package schema
+
+import (
+	"math/rand/v2"
+	"regexp"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// formatPlaceholders are the sample values for string formats: the standard
+// JSON Schema ones plus Kubernetes-style names, quantities and schedules.
+var formatPlaceholders = map[string]string{
+	"date-time":      "2025-01-01T00:00:00Z",
+	"date":           "2025-01-01",
+	"time":           "00:00:00",
+	"email":          "user@example.com",
+	"hostname":       "example.com",
+	"uri":            "https://example.com",
+	"uri-reference":  "/path",
+	"ipv4":           "192.0.2.1",
+	"ipv6":           "2001:db8::1",
+	"uuid":           "00000000-0000-4000-8000-000000000000",
+	"duration":       "30s",
+	"regex":          "^.*$",
+	"json-pointer":   "/path/to/key",
+	"dns-1123-label": "my-app",
+	"k8s-name":       "my-app",
+	"quantity":       "500m",
+	"cron":           "0 * * * *",
+	"image-ref":      "nginx:1.27",
+}
+
+// stringPlaceholder is the sample value for a string schema without a
+// default, const, enum or example: the format placeholder, else a string
+// generated from "pattern" (when the placeholder does not match it), else
+// minLength "x"s. Shared by both samplers.
+func stringPlaceholder(m map[string]any) string {
+	f := stringOr(m["format"], "")
+	s := formatPlaceholders[f]
+	if p, ok := m["pattern"].(string); ok {
+		if re, err := regexp.Compile(p); err == nil && !re.MatchString(s) {
+			// fixed seed: samples are deterministic; prefer a match that also
+			// satisfies the format
+			r := rand.New(rand.NewPCG(1, 1))
+			var first string
+			for try := 0; try < 20; try++ {
+				g, err := randomMatch(p, r)
+				if err != nil {
+					break
+				}
+				// patterns are unanchored, so the placeholder may extend a match
+				for _, c := range []string{g, g + s, s + g} {
+					if re.MatchString(c) && (f == "" || gojsonschema.FormatCheckers.IsFormat(f, c)) {
+						return c
+					}
+				}
+				if try == 0 {
+					first = g
+				}
+			}
+			if first != "" {
+				return first
+			}
+		}
+	}
+	if s == "" {
+		s = strings.Repeat("x", intBound(m, "minLength", 0))
+	}
+	return s
+}
@@ -0,0 +1,76 @@
+package schema
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+func TestFormatPlaceholdersAreValid(t *testing.T) {
+	for f, s := range formatPlaceholders {
+		if !gojsonschema.FormatCheckers.IsFormat(f, s) {
+			t.Errorf("placeholder %q is not a valid %s", s, f)
+		}
+	}
+}
+
+func TestStringPlaceholder(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema map[string]any
+		want   string // exact value, or "" to only check pattern and format
+	}{
+		{name: "no constraints", schema: map[string]any{"type": "string"}, want: ""},
+		{name: "format", schema: map[string]any{"format": "email"}, want: "user@example.com"},
+		{name: "custom format", schema: map[string]any{"format": "quantity"}, want: "500m"},
+		{name: "minLength", schema: map[string]any{"minLength": 3.0}, want: "xxx"},
+		{name: "placeholder already matching the pattern", schema: map[string]any{"format": "hostname", "pattern": `\.com$`}, want: "example.com"},
+		{name: "pattern", schema: map[string]any{"pattern": `^v[0-9]+\.[0-9]+$`}},
+		{name: "pattern and format", schema: map[string]any{"format": "hostname", "pattern": `^api\.`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := stringPlaceholder(tt.schema)
+			if p, ok := tt.schema["pattern"].(string); ok && !regexp.MustCompile(p).MatchString(got) {
+				t.Errorf("placeholder %q does not match %q", got, p)
+			}
+			if f, ok := tt.schema["format"].(string); ok && !gojsonschema.FormatCheckers.IsFormat(f, got) {
+				t.Errorf("placeholder %q is not a valid %s", got, f)
+			}
+			if tt.want != "" && got != tt.want {
+				t.Errorf("placeholder = %q, want %q", got, tt.want)
+			}
+			if again := stringPlaceholder(tt.schema); again != got {
+				t.Errorf("placeholder is not deterministic: %q, then %q", got, again)
+			}
+		})
+	}
+}
+
+func TestSampleValuePreference(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		want   string
+	}{
+		{name: "default first", schema: "{type: string, default: d, enum: [c, d], examples: [c], x-example: c}", want: "d"},
+		{name: "then const", schema: "{type: string, const: c}", want: "c"},
+		{name: "then enum", schema: "{type: string, enum: [e1, e2], examples: [e2]}", want: "e1"},
+		{name: "then examples", schema: "{type: string, examples: [ex1, ex2], x-example: other}", want: "ex1"},
+		{name: "then x-example", schema: "{type: string, format: email, x-example: ops@corp.io}", want: "ops@corp.io"},
+		{name: "then the format placeholder", schema: "{type: string, format: date}", want: "\"2025-01-01\""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildSample(t, "type: object\nproperties:\n  v: "+tt.schema+"\n", SampleOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := "v: " + tt.want; strings.TrimSpace(got) != want {
+				t.Errorf("sample = %q, want %q", got, want)
+			}
+		})
+	}
+}
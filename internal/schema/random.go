@@ -0,0 +1,297 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	y3 "gopkg.in/yaml.v3"
+)
+
+// RandomConfigs generates count random configs that validate against the
+// schema, honoring enum/const, numeric and length bounds, multipleOf,
+// pattern, format and array bounds. Optional properties are included at
+// random and oneOf/anyOf branches are picked at random. The same seed yields
+// the same configs. Candidates that still fail validation (e.g. because of
+// not/if/then, uniqueItems or x-rules) are regenerated a bounded number of
+// times.
+func RandomConfigs(schemaPath string, seed uint64, count int) ([]map[string]any, error) {
+	schemaJSON, err := readSchemaJSON(schemaPath)
+	if err != nil {
+		return nil, err
+	}
+	sch, err := parseSchema(schemaJSON)
+	if err != nil {
+		return nil, err
+	}
+	root, _ := sch.(map[string]any)
+	if root == nil {
+		return nil, fmt.Errorf("schema root must be an object")
+	}
+
+	g := &randomGen{root: root, r: rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))}
+	out := make([]map[string]any, 0, count)
+	for i := 1; i <= count; i++ {
+		var lastErr error
+		for attempt := 0; attempt < maxRandomAttempts; attempt++ {
+			m, _ := g.value(root, 0).(map[string]any)
+			if m == nil {
+				m = map[string]any{}
+			}
+			dataJSON, err := json.Marshal(m)
+			if err != nil {
+				return nil, err
+			}
+			if lastErr = validateJSON(schemaJSON, dataJSON); lastErr == nil {
+				lastErr = checkRules(root, applyDefaultsNode(root, cloneJSON(m)))
+			}
+			if lastErr == nil {
+				out = append(out, m)
+				break
+			}
+		}
+		if lastErr != nil {
+			return nil, fmt.Errorf("config %d: no schema-valid config after %d attempts; last candidate: %w", i, maxRandomAttempts, lastErr)
+		}
+	}
+	return out, nil
+}
+
+// FormatConfig encodes a config map like a sample in opts.Format.
+func FormatConfig(cfg map[string]any, opts SampleOptions) ([]byte, error) {
+	doc := &y3.Node{Kind: y3.DocumentNode, Content: []*y3.Node{valueToYAMLNode(cfg)}}
+	return encodeSample(doc, opts)
+}
+
+const (
+	maxRandomAttempts = 50
+	// past this depth only required properties and minItems elements are
+	// generated, so recursive schemas terminate
+	maxRandomDepth = 6
+)
+
+type randomGen struct {
+	root map[string]any
+	r    *rand.Rand
+}
+
+func (g *randomGen) value(s any, depth int) any {
+	m, ok := s.(map[string]any)
+	if !ok || depth > 3*maxRandomDepth {
+		return nil
+	}
+	m = mergeAllOf(g.root, resolveRef(g.root, m))
+
+	if v, ok := m["const"]; ok {
+		return cloneJSON(v)
+	}
+	if enum, ok := m["enum"].([]any); ok && len(enum) > 0 {
+		return cloneJSON(enum[g.r.IntN(len(enum))])
+	}
+	if alts := alternatives(m); len(alts) > 0 {
+		return g.value(branchSchema(m, alts[g.r.IntN(len(alts))]), depth)
+	}
+
+	kind := schemaKind(m)
+	if types := schemaTypes(m); len(types) > 1 {
+		kind = types[g.r.IntN(len(types))]
+	}
+	switch kind {
+	case "object":
+		return g.object(m, depth)
+	case "array":
+		return g.array(m, depth)
+	case "integer":
+		return g.number(m, true)
+	case "number":
+		return g.number(m, false)
+	case "boolean":
+		return g.r.IntN(2) == 0
+	case "null":
+		return nil
+	}
+	return g.str(m)
+}
+
+func (g *randomGen) object(m map[string]any, depth int) map[string]any {
+	out := map[string]any{}
+	props, _ := m["properties"].(map[string]any)
+	required := stringSet(m["required"])
+	for _, k := range sortedKeys(props) {
+		if !required[k] && (depth >= maxRandomDepth || g.r.IntN(2) == 0) {
+			continue
+		}
+		out[k] = g.value(props[k], depth+1)
+	}
+	if aps, ok := m["additionalProperties"].(map[string]any); ok && len(props) == 0 && depth < maxRandomDepth {
+		for i := g.r.IntN(3); i > 0; i-- {
+			out[g.word(3, 8)] = g.value(aps, depth+1)
+		}
+	}
+	return out
+}
+
+func (g *randomGen) array(m map[string]any, depth int) []any {
+	lo, hi := intBound(m, "minItems", 0), intBound(m, "maxItems", -1)
+	if hi < 0 {
+		hi = lo + 3
+	}
+	if depth >= maxRandomDepth {
+		hi = lo
+	}
+	n := lo + g.r.IntN(hi-lo+1)
+	out := make([]any, 0, n)
+	unique, _ := m["uniqueItems"].(bool)
+	seen := map[string]bool{}
+	for len(out) < n {
+		var v any
+		for try := 0; try < 10; try++ {
+			v = g.value(m["items"], depth+1)
+			if !unique || !seen[formatValue(v)] {
+				break
+			}
+		}
+		seen[formatValue(v)] = true
+		out = append(out, v)
+	}
+	return out
+}
+
+func (g *randomGen) number(m map[string]any, integer bool) any {
+	lo, hasLo := m["minimum"].(float64)
+	hi, hasHi := m["maximum"].(float64)
+	step := 1e-2
+	if integer {
+		step = 1
+	}
+	// draft 6+ numeric exclusive bounds, and draft 4 boolean ones
+	if v, ok := m["exclusiveMinimum"].(float64); ok && (!hasLo || v >= lo) {
+		lo, hasLo = v+step, true
+	} else if b, _ := m["exclusiveMinimum"].(bool); b && hasLo {
+		lo += step
+	}
+	if v, ok := m["exclusiveMaximum"].(float64); ok && (!hasHi || v <= hi) {
+		hi, hasHi = v-step, true
+	} else if b, _ := m["exclusiveMaximum"].(bool); b && hasHi {
+		hi -= step
+	}
+	switch {
+	case !hasLo && !hasHi:
+		lo, hi = 0, 100
+	case !hasLo:
+		lo = hi - 100
+	case !hasHi:
+		hi = lo + 100
+	}
+
+	if mo, ok := m["multipleOf"].(float64); ok && mo > 0 {
+		kLo, kHi := math.Ceil(lo/mo), math.Floor(hi/mo)
+		k := kLo
+		if kHi > kLo {
+			k += float64(g.r.Int64N(int64(kHi-kLo) + 1))
+		}
+		if integer {
+			return int64(k * mo)
+		}
+		return k * mo
+	}
+	if integer {
+		lo, hi = math.Ceil(lo), math.Floor(hi)
+		if hi <= lo {
+			return int64(lo)
+		}
+		return int64(lo) + g.r.Int64N(int64(hi-lo)+1)
+	}
+	return math.Round((lo+g.r.Float64()*(hi-lo))*100) / 100
+}
+
+func (g *randomGen) str(m map[string]any) string {
+	lo, hi := intBound(m, "minLength", 1), intBound(m, "maxLength", -1)
+	if hi < 0 {
+		hi = lo + 11
+	}
+	lo = min(lo, hi)
+	if p, ok := m["pattern"].(string); ok {
+		for try := 0; try < 10; try++ {
+			s, err := randomMatch(p, g.r)
+			if err != nil {
+				break
+			}
+			if n := len([]rune(s)); n >= lo && n <= hi {
+				return s
+			}
+		}
+	}
+	if f, ok := m["format"].(string); ok {
+		if s, ok := g.format(f); ok {
+			return s
+		}
+	}
+	return g.word(lo, hi)
+}
+
+// format returns a random value for the common string formats, or the
+// sample placeholder for the others it knows.
+func (g *randomGen) format(f string) (string, bool) {
+	t := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(g.r.Int64N(int64(5*365*24*time.Hour))) / time.Second * time.Second)
+	switch f {
+	case "date-time":
+		return t.Format(time.RFC3339), true
+	case "date":
+		return t.Format(time.DateOnly), true
+	case "time":
+		return t.Format(time.TimeOnly) + "Z", true
+	case "email":
+		return g.word(3, 8) + "@" + g.word(3, 8) + ".com", true
+	case "hostname":
+		return g.word(3, 8) + ".example.com", true
+	case "uri":
+		return "https://" + g.word(3, 8) + ".example.com/" + g.word(1, 8), true
+	case "ipv4":
+		return fmt.Sprintf("%d.%d.%d.%d", 1+g.r.IntN(223), g.r.IntN(256), g.r.IntN(256), 1+g.r.IntN(254)), true
+	case "ipv6":
+		parts := make([]string, 8)
+		for i := range parts {
+			parts[i] = fmt.Sprintf("%x", g.r.IntN(0x10000))
+		}
+		return strings.Join(parts, ":"), true
+	case "uuid":
+		b := make([]byte, 16)
+		for i := range b {
+			b[i] = byte(g.r.IntN(256))
+		}
+		b[6], b[8] = b[6]&0x0f|0x40, b[8]&0x3f|0x80
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), true
+	}
+	s, ok := formatPlaceholders[f]
+	return s, ok
+}
+
+// word returns a random lowercase alphanumeric string (starting with a
+// letter) of length lo..hi.
+func (g *randomGen) word(lo, hi int) string {
+	n := lo
+	if hi > lo {
+		n += g.r.IntN(hi - lo + 1)
+	}
+	b := make([]byte, n)
+	for i := range b {
+		if i == 0 {
+			b[i] = alnum[g.r.IntN(26)]
+		} else {
+			b[i] = alnum[g.r.IntN(len(alnum))]
+		}
+	}
+	return string(b)
+}
+
+// intBound returns the non-negative integer keyword k of m, or def.
+func intBound(m map[string]any, k string, def int) int {
+	if v, ok := m[k].(float64); ok && v >= 0 {
+		return int(v)
+	}
+	return def
+}
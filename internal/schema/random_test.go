@@ -0,0 +1,115 @@
+package schema
+
+import (
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestRandomConfigs(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		check   func(t *testing.T, cfg map[string]any)
+		wantErr string
+	}{
+		{
+			name:   "bounds, enum and pattern",
+			schema: "type: object\nrequired: [port, env, tag, hosts]\nproperties:\n  port: {type: integer, minimum: 1024, maximum: 2048, multipleOf: 8}\n  env: {enum: [dev, prod]}\n  tag: {type: string, pattern: \"^v[0-9]+\\\\.[0-9]+$\"}\n  hosts: {type: array, minItems: 1, maxItems: 3, items: {type: string, format: hostname}}\n",
+			check: func(t *testing.T, cfg map[string]any) {
+				port, _ := cfg["port"].(int64)
+				if f, ok := cfg["port"].(float64); ok {
+					port = int64(f)
+				}
+				if port < 1024 || port > 2048 || port%8 != 0 {
+					t.Errorf("port = %v", cfg["port"])
+				}
+				if env := cfg["env"]; env != "dev" && env != "prod" {
+					t.Errorf("env = %v", env)
+				}
+				if tag, _ := cfg["tag"].(string); !regexp.MustCompile(`^v[0-9]+\.[0-9]+$`).MatchString(tag) {
+					t.Errorf("tag = %q", tag)
+				}
+				if hosts, _ := cfg["hosts"].([]any); len(hosts) < 1 || len(hosts) > 3 {
+					t.Errorf("hosts = %v", cfg["hosts"])
+				}
+			},
+		},
+		{
+			name:   "x-rules hold",
+			schema: "type: object\nrequired: [min, max]\nproperties:\n  min: {type: integer, minimum: 0, maximum: 10}\n  max: {type: integer, minimum: 0, maximum: 10}\nx-rules:\n  - self.min <= self.max\n",
+			check: func(t *testing.T, cfg map[string]any) {
+				if celValue(cfg["min"]).(int64) > celValue(cfg["max"]).(int64) {
+					t.Errorf("min > max in %v", cfg)
+				}
+			},
+		},
+		{
+			name:    "unsatisfiable schema",
+			schema:  "type: object\nrequired: [n]\nproperties:\n  n: {type: integer, minimum: 5}\nnot: {required: [n]}\n",
+			wantErr: "no schema-valid config after 50 attempts",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := filepath.Join(t.TempDir(), "config.schema.yaml")
+			if err := os.WriteFile(p, []byte(tt.schema), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			cfgs, err := RandomConfigs(p, 7, 20)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(cfgs) != 20 {
+				t.Fatalf("got %d configs, want 20", len(cfgs))
+			}
+			for _, cfg := range cfgs {
+				tt.check(t, cfg)
+			}
+			again, err := RandomConfigs(p, 7, 20)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(cfgs, again) {
+				t.Error("the same seed produced different configs")
+			}
+		})
+	}
+}
+
+func TestRandomMatch(t *testing.T) {
+	patterns := []string{
+		`^[a-z]+$`,
+		`^v\d+\.\d+\.\d+$`,
+		`^(dev|staging|prod)-[0-9]{2,4}$`,
+		`^[A-Z][a-z]*( [A-Z][a-z]*)?$`,
+		`api`,
+		`^\w+@example\.com$`,
+	}
+	r := rand.New(rand.NewPCG(1, 2))
+	for _, p := range patterns {
+		re := regexp.MustCompile(p)
+		for i := 0; i < 20; i++ {
+			s, err := randomMatch(p, r)
+			if err != nil {
+				t.Fatalf("randomMatch(%q): %v", p, err)
+			}
+			if !re.MatchString(s) {
+				t.Errorf("randomMatch(%q) = %q, which does not match", p, s)
+			}
+		}
+	}
+	if _, err := randomMatch(`(`, r); err == nil {
+		t.Error("invalid pattern: want an error")
+	}
+}
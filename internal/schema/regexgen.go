@@ -0,0 +1,106 @@
+package schema
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"unicode"
+)
+
+// randomMatch generates a string matching the (unanchored, JSON Schema style)
+// regular expression pattern, preferring printable ASCII. Constructs the
+// generator only approximates (word boundaries, case folding) are checked by
+// matching the result; it gives up after a few attempts.
+func randomMatch(pattern string, r *rand.Rand) (string, error) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", fmt.Errorf("pattern %q: %w", pattern, err)
+	}
+	re = re.Simplify()
+	check, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", fmt.Errorf("pattern %q: %w", pattern, err)
+	}
+	for attempt := 0; attempt < 10; attempt++ {
+		var b strings.Builder
+		genRegexp(&b, re, r)
+		if s := b.String(); check.MatchString(s) {
+			return s, nil
+		}
+	}
+	return "", fmt.Errorf("pattern %q: could not generate a matching string", pattern)
+}
+
+// maxRegexRepeat bounds the extra repetitions of *, + and open {n,}.
+const maxRegexRepeat = 3
+
+func genRegexp(b *strings.Builder, re *syntax.Regexp, r *rand.Rand) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		for _, c := range re.Rune {
+			if re.Flags&syntax.FoldCase != 0 && r.IntN(2) == 0 {
+				c = unicode.SimpleFold(c)
+			}
+			b.WriteRune(c)
+		}
+	case syntax.OpCharClass:
+		b.WriteRune(classRune(re.Rune, r))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteByte(alnum[r.IntN(len(alnum))])
+	case syntax.OpCapture:
+		genRegexp(b, re.Sub[0], r)
+	case syntax.OpStar:
+		genRepeat(b, re.Sub[0], 0, maxRegexRepeat, r)
+	case syntax.OpPlus:
+		genRepeat(b, re.Sub[0], 1, 1+maxRegexRepeat, r)
+	case syntax.OpQuest:
+		genRepeat(b, re.Sub[0], 0, 1, r)
+	case syntax.OpRepeat:
+		hi := re.Max
+		if hi < 0 {
+			hi = re.Min + maxRegexRepeat
+		}
+		genRepeat(b, re.Sub[0], re.Min, hi, r)
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			genRegexp(b, sub, r)
+		}
+	case syntax.OpAlternate:
+		genRegexp(b, re.Sub[r.IntN(len(re.Sub))], r)
+	}
+	// anchors, word boundaries and empty matches produce no text
+}
+
+func genRepeat(b *strings.Builder, re *syntax.Regexp, lo, hi int, r *rand.Rand) {
+	n := lo
+	if hi > lo {
+		n += r.IntN(hi - lo + 1)
+	}
+	for i := 0; i < n; i++ {
+		genRegexp(b, re, r)
+	}
+}
+
+const alnum = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// classRune picks a rune from a character class (pairs of inclusive ranges),
+// from its printable ASCII part when it has one, so negated classes like
+// [^/] do not yield control or exotic characters.
+func classRune(ranges []rune, r *rand.Rand) rune {
+	var ascii []rune
+	for i := 0; i+1 < len(ranges); i += 2 {
+		for c := max(ranges[i], 0x21); c <= min(ranges[i+1], 0x7e); c++ {
+			ascii = append(ascii, c)
+		}
+	}
+	if len(ascii) > 0 {
+		return ascii[r.IntN(len(ascii))]
+	}
+	if len(ranges) < 2 {
+		return 'x'
+	}
+	i := r.IntN(len(ranges)/2) * 2
+	return ranges[i] + rune(r.IntN(int(ranges[i+1]-ranges[i])+1))
+}
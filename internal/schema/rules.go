@@ -0,0 +1,180 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/besrabasant/valuesctl/internal/celx"
+)
+
+// A rule is one "x-rules" entry: a CEL expression over "self" (the config
+// value at the schema node carrying the rule) that must evaluate to true.
+//
+//	x-rules:
+//	  - rule: "!self.ha || self.replicas >= 2"
+//	    message: "replicas must be at least 2 when ha is enabled"
+//	    fieldPath: replicas
+//	  - "self.minReplicas <= self.maxReplicas"
+type rule struct {
+	Expr      string
+	Message   string
+	FieldPath string // dotted path below the node to report, optional
+}
+
+// schemaRules decodes the "x-rules" of schema node m.
+func schemaRules(m map[string]any) ([]rule, error) {
+	raw, ok := m["x-rules"]
+	if !ok {
+		return nil, nil
+	}
+	list, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("x-rules must be a list")
+	}
+	var rules []rule
+	for i, e := range list {
+		switch t := e.(type) {
+		case string:
+			rules = append(rules, rule{Expr: t})
+		case map[string]any:
+			r := rule{Expr: stringOr(t["rule"], ""), Message: stringOr(t["message"], ""), FieldPath: stringOr(t["fieldPath"], "")}
+			if r.Expr == "" {
+				return nil, fmt.Errorf("x-rules/%d: missing rule", i)
+			}
+			rules = append(rules, r)
+		default:
+			return nil, fmt.Errorf("x-rules/%d: must be a CEL string or a {rule, message, fieldPath} map", i)
+		}
+	}
+	return rules, nil
+}
+
+// ruleViolation is a failed (or failing to evaluate) rule.
+type ruleViolation struct {
+	Path    string // dotted config path, "" for the root
+	Message string
+}
+
+func (v ruleViolation) String() string {
+	p := v.Path
+	if p == "" {
+		p = "(root)"
+	}
+	return fmt.Sprintf("%s: %s", p, v.Message)
+}
+
+// checkRules evaluates the "x-rules" of sch against cfg (a JSON view of the
+// config with defaults applied). Rules are found by walking the schema along
+// the values present in cfg (properties, additionalProperties, items, allOf
+// and $ref); oneOf/anyOf branches are not entered. Null values count as
+// absent, so has(self.x) is false for them.
+func checkRules(sch any, cfg any) error {
+	root, _ := sch.(map[string]any)
+	rc := &ruleChecker{root: root}
+	rc.walk(root, dropNulls(cfg), "")
+	if len(rc.violations) == 0 {
+		return nil
+	}
+	var b strings.Builder
+	for _, v := range rc.violations {
+		fmt.Fprintf(&b, "- %s\n", v)
+	}
+	return errors.New("rule violations:\n" + b.String())
+}
+
+type ruleChecker struct {
+	root       map[string]any
+	violations []ruleViolation
+}
+
+func (rc *ruleChecker) walk(s any, v any, path string) {
+	m, ok := s.(map[string]any)
+	if !ok {
+		return
+	}
+	m = mergeAllOf(rc.root, resolveRef(rc.root, m))
+
+	rules, err := schemaRules(m)
+	if err != nil {
+		rc.violations = append(rc.violations, ruleViolation{Path: path, Message: err.Error()})
+	}
+	for _, r := range rules {
+		if msg, ok := evalRule(r, v); !ok {
+			p := path
+			if fp := strings.TrimPrefix(r.FieldPath, "."); fp != "" {
+				p = joinPath(path, fp)
+			}
+			rc.violations = append(rc.violations, ruleViolation{Path: p, Message: msg})
+		}
+	}
+
+	switch t := v.(type) {
+	case map[string]any:
+		props, _ := m["properties"].(map[string]any)
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if sub, ok := props[k]; ok {
+				rc.walk(sub, t[k], joinPath(path, k))
+			} else if aps, ok := m["additionalProperties"].(map[string]any); ok {
+				rc.walk(aps, t[k], joinPath(path, k))
+			}
+		}
+	case []any:
+		for i, e := range t {
+			rc.walk(m["items"], e, fmt.Sprintf("%s[%d]", path, i))
+		}
+	}
+}
+
+// evalRule runs r with self bound to v, returning the message to report when
+// the rule does not hold or cannot be evaluated.
+func evalRule(r rule, v any) (string, bool) {
+	prg, err := celx.Compile(r.Expr, "self")
+	if err != nil {
+		return err.Error(), false
+	}
+	out, _, err := prg.Eval(map[string]any{"self": celValue(v)})
+	if err != nil {
+		return fmt.Sprintf("rule %q: %v", r.Expr, err), false
+	}
+	if b, ok := out.Value().(bool); !ok {
+		return fmt.Sprintf("rule %q: must evaluate to a bool, got %v", r.Expr, out.Type()), false
+	} else if b {
+		return "", true
+	}
+	if r.Message != "" {
+		return r.Message, false
+	}
+	return fmt.Sprintf("failed rule: %s", r.Expr), false
+}
+
+// celValue converts JSON numbers with integral values to int64, so rules can
+// use integer literals and arithmetic (self.replicas + 1).
+func celValue(v any) any {
+	switch t := v.(type) {
+	case float64:
+		if t == math.Trunc(t) && math.Abs(t) < 1<<53 {
+			return int64(t)
+		}
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, e := range t {
+			out[k] = celValue(e)
+		}
+		return out
+	case []any:
+		out := make([]any, len(t))
+		for i, e := range t {
+			out[i] = celValue(e)
+		}
+		return out
+	}
+	return v
+}
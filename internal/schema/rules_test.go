@@ -0,0 +1,165 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckRules(t *testing.T) {
+	const schema = `type: object
+x-rules:
+  - rule: "!self.ha || self.replicas >= 2"
+    message: replicas must be at least 2 when ha is enabled
+    fieldPath: replicas
+properties:
+  ha: {type: boolean, default: false}
+  replicas: {type: integer, default: 1}
+  name: {type: string, default: api, x-rules: ["self.startsWith('a')"]}
+  hosts:
+    type: array
+    items: {$ref: "#/definitions/host"}
+  limits:
+    type: object
+    default: {min: 1, max: 2}
+    x-rules: ["self.min <= self.max"]
+definitions:
+  host:
+    type: object
+    x-rules: ["self.port < 65536"]
+`
+	tests := []struct {
+		name    string
+		config  string
+		wantErr []string
+	}{
+		{name: "defaults satisfy the rules", config: "{}\n"},
+		{name: "rule passes with a set value", config: "ha: true\nreplicas: 3\n"},
+		{
+			name:    "rule fails on a default",
+			config:  "ha: true\n",
+			wantErr: []string{"- replicas: replicas must be at least 2 when ha is enabled"},
+		},
+		{
+			name:    "bare expression below a $ref",
+			config:  "hosts:\n  - port: 80\n  - port: 70000\n",
+			wantErr: []string{"- hosts[1]: failed rule: self.port < 65536"},
+		},
+		{
+			name:    "default object checked",
+			config:  "limits: {min: 3, max: 2}\nname: bee\n",
+			wantErr: []string{"- limits: failed rule: self.min <= self.max", "- name: failed rule: self.startsWith('a')"},
+		},
+	}
+	dir := t.TempDir()
+	schemaPath := filepath.Join(dir, "config.schema.yaml")
+	if err := os.WriteFile(schemaPath, []byte(schema), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfgPath := filepath.Join(dir, "config.yaml")
+			if err := os.WriteFile(cfgPath, []byte(tt.config), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, _, err := PrepareConfig(schemaPath, cfgPath, ConfigOptions{Validate: true})
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("no error, want %q", tt.wantErr)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error:\n%v\nwant it to contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckRulesErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		want   string
+	}{
+		{name: "not a list", schema: "x-rules: self\n", want: "(root): x-rules must be a list"},
+		{name: "does not compile", schema: "x-rules: [\"self.\"]\n", want: `(root): rule "self.": ERROR`},
+		{name: "not a bool", schema: "x-rules: [\"1 + 1\"]\n", want: `(root): rule "1 + 1": must evaluate to a bool, got int`},
+		{name: "missing field", schema: "x-rules: [\"self.a == 1\"]\n", want: `(root): rule "self.a == 1": no such key: a`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkRules(yamlSchema(t, tt.schema), map[string]any{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyDefaults(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		cfg    any
+		want   any
+	}{
+		{
+			name:   "nested object",
+			schema: "type: object\nproperties:\n  app:\n    type: object\n    properties:\n      port: {type: integer, default: 80}\n",
+			cfg:    map[string]any{},
+			want:   map[string]any{"app": map[string]any{"port": 80.0}},
+		},
+		{
+			name:   "set values are kept",
+			schema: "type: object\nproperties:\n  port: {type: integer, default: 80}\n",
+			cfg:    map[string]any{"port": 8080.0},
+			want:   map[string]any{"port": 8080.0},
+		},
+		{
+			name:   "through a $ref",
+			schema: "definitions:\n  app:\n    type: object\n    properties:\n      port: {default: 80}\ntype: object\nproperties:\n  app: {$ref: \"#/definitions/app\"}\n",
+			cfg:    map[string]any{},
+			want:   map[string]any{"app": map[string]any{"port": 80.0}},
+		},
+		{
+			name:   "recursive $ref stops where the config ends",
+			schema: "definitions:\n  node:\n    type: object\n    properties:\n      name: {default: x}\n      next: {$ref: \"#/definitions/node\"}\n$ref: \"#/definitions/node\"\n",
+			cfg:    map[string]any{"next": map[string]any{"name": "b"}},
+			want:   map[string]any{"name": "x", "next": map[string]any{"name": "b", "next": nil}},
+		},
+		{
+			name:   "type list",
+			schema: "type: object\nproperties:\n  tls:\n    type: [object, \"null\"]\n    properties:\n      enabled: {default: false}\n",
+			cfg:    map[string]any{},
+			want:   map[string]any{"tls": map[string]any{"enabled": false}},
+		},
+		{
+			name:   "type list keeps a value of another type",
+			schema: "type: object\nproperties:\n  tls:\n    type: [string, object]\n    properties:\n      enabled: {default: false}\n",
+			cfg:    map[string]any{"tls": "auto"},
+			want:   map[string]any{"tls": "auto"},
+		},
+		{
+			name:   "allOf branches",
+			schema: "type: object\nallOf:\n  - properties:\n      a: {default: 1}\n  - properties:\n      b: {default: 2}\n",
+			cfg:    map[string]any{},
+			want:   map[string]any{"a": 1.0, "b": 2.0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := applyDefaultsNode(yamlSchema(t, tt.schema), tt.cfg)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
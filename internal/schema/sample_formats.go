@@ -0,0 +1,144 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	y3 "gopkg.in/yaml.v3"
+)
+
+// encodeSample renders a sample document in opts.Format. JSON and TOML carry
+// the same data as the YAML sample (without comments; TOML has no null, so
+// null values are left out); env flattens it into KEY=value lines.
+func encodeSample(doc *y3.Node, opts SampleOptions) ([]byte, error) {
+	switch opts.Format {
+	case "", "yaml":
+		var buf bytes.Buffer
+		enc := y3.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(doc); err != nil {
+			return nil, fmt.Errorf("yaml encode: %w", err)
+		}
+		return bytes.TrimSpace(buf.Bytes()), nil
+	case "env":
+		sep := opts.EnvSeparator
+		if sep == "" {
+			sep = "__"
+		}
+		var lines []string
+		writeEnv(&lines, doc.Content[0], "", sep)
+		return []byte(strings.Join(lines, "\n")), nil
+	}
+
+	var v any
+	if err := doc.Decode(&v); err != nil {
+		return nil, err
+	}
+	switch opts.Format {
+	case "json":
+		out, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("json encode: %w", err)
+		}
+		return out, nil
+	case "toml":
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("toml output needs an object at the schema root")
+		}
+		out, err := toml.Marshal(dropNulls(m))
+		if err != nil {
+			return nil, fmt.Errorf("toml encode: %w", err)
+		}
+		return bytes.TrimSpace(out), nil
+	}
+	return nil, fmt.Errorf("invalid --format %q (want yaml, json, toml or env)", opts.Format)
+}
+
+// writeEnv flattens n into NAME=value lines: keys are upper-cased and joined
+// with sep, array elements are addressed by index, and key descriptions
+// become comments.
+func writeEnv(lines *[]string, n *y3.Node, prefix, sep string) {
+	switch n.Kind {
+	case y3.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k := n.Content[i]
+			if c := strings.TrimSpace(k.HeadComment); c != "" {
+				for _, l := range strings.Split(c, "\n") {
+					if !strings.HasPrefix(l, "#") {
+						l = "# " + l
+					}
+					*lines = append(*lines, l)
+				}
+			}
+			writeEnv(lines, n.Content[i+1], envJoin(prefix, envName(k.Value), sep), sep)
+		}
+	case y3.SequenceNode:
+		for i, e := range n.Content {
+			writeEnv(lines, e, envJoin(prefix, strconv.Itoa(i), sep), sep)
+		}
+	case y3.ScalarNode:
+		v := n.Value
+		if n.Tag == "!!null" {
+			v = ""
+		}
+		*lines = append(*lines, prefix+"="+envQuote(v))
+	}
+}
+
+func envJoin(prefix, name, sep string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + sep + name
+}
+
+// envName upper-cases a key and replaces characters not allowed in variable
+// names with "_".
+func envName(k string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		}
+		return '_'
+	}, k)
+}
+
+// envQuote double-quotes values that a .env parser would otherwise split or
+// interpret.
+func envQuote(v string) string {
+	if v == "" || strings.ContainsAny(v, " \t\n\"'#$\\`=") {
+		return strconv.Quote(v)
+	}
+	return v
+}
+
+// dropNulls removes nil values from maps (recursively), for TOML.
+func dropNulls(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, e := range t {
+			if e != nil {
+				out[k] = dropNulls(e)
+			}
+		}
+		return out
+	case []any:
+		out := make([]any, 0, len(t))
+		for _, e := range t {
+			if e != nil {
+				out = append(out, dropNulls(e))
+			}
+		}
+		return out
+	}
+	return v
+}
@@ -0,0 +1,107 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSampleFormats(t *testing.T) {
+	const schema = `type: object
+properties:
+  name: {type: string, description: App name, default: api}
+  replicas: {type: integer, default: 2}
+  debug: {type: boolean, default: false}
+  image:
+    type: object
+    properties:
+      tag: {type: string, default: "v1 \"x\""}
+  hosts: {type: array, default: [a, b]}
+  app-id: {type: string, default: id}
+  empty: {type: "null"}
+`
+	tests := []struct {
+		name    string
+		opts    SampleOptions
+		want    string
+		wantErr string
+	}{
+		{
+			name: "json",
+			opts: SampleOptions{Format: "json"},
+			want: `{
+  "app-id": "id",
+  "debug": false,
+  "empty": null,
+  "hosts": [
+    "a",
+    "b"
+  ],
+  "image": {
+    "tag": "v1 \"x\""
+  },
+  "name": "api",
+  "replicas": 2
+}`,
+		},
+		{
+			name: "toml drops nulls",
+			opts: SampleOptions{Format: "toml"},
+			want: `app-id = 'id'
+debug = false
+hosts = ['a', 'b']
+name = 'api'
+replicas = 2
+
+[image]
+tag = 'v1 "x"'`,
+		},
+		{
+			name: "env",
+			opts: SampleOptions{Format: "env"},
+			want: `APP_ID=id
+DEBUG=false
+EMPTY=""
+HOSTS__0=a
+HOSTS__1=b
+IMAGE__TAG="v1 \"x\""
+# App name
+NAME=api
+REPLICAS=2`,
+		},
+		{
+			name: "env with a custom separator",
+			opts: SampleOptions{Format: "env", EnvSeparator: "_"},
+			want: `APP_ID=id
+DEBUG=false
+EMPTY=""
+HOSTS_0=a
+HOSTS_1=b
+IMAGE_TAG="v1 \"x\""
+# App name
+NAME=api
+REPLICAS=2`,
+		},
+		{
+			name:    "unknown format",
+			opts:    SampleOptions{Format: "ini"},
+			wantErr: `invalid --format "ini"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildSample(t, schema, tt.opts)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.TrimSpace(got) != tt.want {
+				t.Errorf("sample:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
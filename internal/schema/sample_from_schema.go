@@ -2,63 +2,126 @@ package schema
 
 import (
 	"bytes"
-	"encoding/json"
 	"fmt"
 	"math"
-	"os"
+	"slices"
 	"sort"
 	"strings"
 
 	y3 "gopkg.in/yaml.v3"
-	"sigs.k8s.io/yaml"
 )
 
+// SampleOptions controls how samples are generated.
+type SampleOptions struct {
+	// ArrayItems is how many example elements arrays get: "0" (empty, the
+	// default), "1", or "minItems" (as many as the schema requires).
+	ArrayItems string
+	// Mode selects which properties are emitted: "full" (all, the default),
+	// "minimal" (only required ones) or "annotated" (required ones, with the
+	// optional ones as commented-out YAML showing their defaults).
+	Mode string
+	// CommentStyle is "description" (the default), "full" (title header,
+	// description and a type/constraints/default line) or "none".
+	CommentStyle string
+	// Format is the output format: "yaml" (the default), "json", "toml" or
+	// "env" (nested keys flattened into NAME=value lines joined by EnvSeparator,
+	// "__" by default).
+	Format       string
+	EnvSeparator string
+
+	root    map[string]any // schema root, for resolving local $refs
+	refPath []string       // $refs being expanded, to stop recursive definitions
+}
+
+// enterRef records that m's $ref is being expanded; it reports false when
+// that definition is already being expanded higher up (a recursive schema).
+func (o *SampleOptions) enterRef(m map[string]any) bool {
+	ref, ok := m["$ref"].(string)
+	if !ok {
+		return true
+	}
+	if slices.Contains(o.refPath, ref) {
+		return false
+	}
+	o.refPath = append(slices.Clip(o.refPath), ref)
+	return true
+}
+
+// inRef reports whether m refers to a definition already being expanded;
+// arrays of such items stay empty instead of nesting an element.
+func (o SampleOptions) inRef(m map[string]any) bool {
+	ref, ok := m["$ref"].(string)
+	return ok && slices.Contains(o.refPath, ref)
+}
+
+// resolve follows a local $ref and merges allOf branches, so m's keywords
+// (and its comment) are those of the composed schema.
+func (o SampleOptions) resolve(m map[string]any) map[string]any {
+	return mergeAllOf(o.root, resolveRef(o.root, m))
+}
+
 // BuildSampleFromSchema reads a JSON Schema (YAML or JSON) and produces a sample YAML config.
-// It prefers "default", then "const", then first "enum", else a zero-ish placeholder by "type".
+// It prefers "default", then "const", then first "enum", then first "examples",
+// then "x-example", else a placeholder by "type" (format- and pattern-aware for strings).
 // For objects/arrays, it recurses into "properties"/"items".
-// For anyOf/oneOf/allOf: it picks first branch (and merges for allOf).
-func BuildSampleFromSchema(schemaPath string) ([]byte, error) {
-	raw, err := os.ReadFile(schemaPath)
-	if err != nil {
-		return nil, fmt.Errorf("read schema: %w", err)
+// allOf branches are merged at the schema level; for anyOf/oneOf it picks the
+// first branch and adds the other branches as commented-out alternatives.
+func BuildSampleFromSchema(schemaPath string, opts SampleOptions) ([]byte, error) {
+	switch opts.ArrayItems {
+	case "", "0", "1", "minItems":
+	default:
+		return nil, fmt.Errorf("invalid --array-items %q (want 0, 1 or minItems)", opts.ArrayItems)
 	}
-
-	// Normalize to JSON
-	schemaJSON := raw
-	if looksLikeYAML(schemaPath, raw) {
-		schemaJSON, err = yaml.YAMLToJSON(raw)
-		if err != nil {
-			return nil, fmt.Errorf("schema YAML->JSON: %w", err)
-		}
+	switch opts.Mode {
+	case "", "full", "minimal", "annotated":
+	default:
+		return nil, fmt.Errorf("invalid --mode %q (want minimal, full or annotated)", opts.Mode)
 	}
-
-	var sch any
-	if err := json.Unmarshal(schemaJSON, &sch); err != nil {
-		return nil, fmt.Errorf("schema json unmarshal: %w", err)
+	switch opts.CommentStyle {
+	case "", "description", "full", "none":
+	default:
+		return nil, fmt.Errorf("invalid --comment-style %q (want description, full or none)", opts.CommentStyle)
+	}
+	switch opts.Format {
+	case "", "yaml", "json", "toml", "env":
+	default:
+		return nil, fmt.Errorf("invalid --format %q (want yaml, json, toml or env)", opts.Format)
+	}
+	sch, err := loadSchema(schemaPath)
+	if err != nil {
+		return nil, err
 	}
 
-	sample := sampleNodeWithComments(sch)
+	opts.root, _ = sch.(map[string]any)
+	sample := sampleNodeWithComments(sch, opts)
 
 	doc := &y3.Node{
 		Kind:    y3.DocumentNode,
 		Content: []*y3.Node{sample},
 	}
-
-	var buf bytes.Buffer
-	enc := y3.NewEncoder(&buf)
-	enc.SetIndent(2)
-	if err := enc.Encode(doc); err != nil {
-		return nil, fmt.Errorf("yaml encode: %w", err)
+	if root, ok := sch.(map[string]any); ok {
+		if opts.CommentStyle == "full" {
+			// the root's title and description head the document
+			doc.HeadComment = joinComments(strings.TrimSpace(stringOr(root["title"], "")), strings.TrimSpace(stringOr(root["description"], "")))
+		}
+		doc.FootComment = alternativesComment(root, opts, func(n *y3.Node) *y3.Node { return n })
 	}
-	return bytes.TrimSpace(buf.Bytes()), nil
+
+	return encodeSample(doc, opts)
 }
 
-func sampleNodeWithComments(s any) *y3.Node {
+func sampleNodeWithComments(s any, opts SampleOptions) *y3.Node {
 	m, ok := s.(map[string]any)
 	if !ok {
 		// Fallback: just scalarize
 		return valueToYAMLNode(nil)
 	}
+	if !opts.enterRef(m) {
+		return valueToYAMLNode(nil)
+	}
+	// allOf: merge the branches' properties, required and descriptions first,
+	// so composed objects are built (and commented) like plain ones
+	m = opts.resolve(m)
 
 	// Default/const/enum take precedence and short-circuit (use the value as-is, no per-key comments)
 	if v, ok := m["default"]; ok {
@@ -70,65 +133,62 @@ func sampleNodeWithComments(s any) *y3.Node {
 	if enum, ok := m["enum"].([]any); ok && len(enum) > 0 {
 		return valueToYAMLNode(enum[0])
 	}
-
-	// Combine allOf subschemas (shallow merge)
-	if allOf, ok := m["allOf"].([]any); ok && len(allOf) > 0 {
-		merged := map[string]any{}
-		for _, sub := range allOf {
-			subSample := sampleForSchemaPlain(sub)
-			if subObj, ok := subSample.(map[string]any); ok {
-				for k, v := range subObj {
-					merged[k] = v
-				}
-			}
-		}
-		if len(merged) > 0 {
-			// Use merged plain map as a value node (no per-field comments; if you
-			// want comments even when allOf applies, you must compute schema-level
-			// merged properties and call object builder below instead).
-			return valueToYAMLNode(merged)
-		}
-		// fallback to first branch sample
-		return sampleNodeWithComments(allOf[0])
+	if ex, ok := m["examples"].([]any); ok && len(ex) > 0 {
+		return valueToYAMLNode(ex[0])
 	}
-
-	// anyOf/oneOf: choose first branch
-	if anyOf, ok := m["anyOf"].([]any); ok && len(anyOf) > 0 {
-		return sampleNodeWithComments(anyOf[0])
+	if v, ok := m["x-example"]; ok {
+		return valueToYAMLNode(v)
 	}
-	if oneOf, ok := m["oneOf"].([]any); ok && len(oneOf) > 0 {
-		return sampleNodeWithComments(oneOf[0])
+
+	// anyOf/oneOf: the first branch is sampled (combined with the node's own
+	// keywords); callers render the others via alternativesComment
+	if alts := alternatives(m); len(alts) > 0 {
+		return sampleNodeWithComments(branchSchema(m, alts[0]), opts)
 	}
 
-	switch m["type"] {
+	switch schemaKind(m) {
 	case "object":
 		// Build a mapping node and attach HeadComment from each property's description
 		props, _ := m["properties"].(map[string]any)
 		node := &y3.Node{Kind: y3.MappingNode}
 
-		// Deterministic key order
+		// Deterministic key order. Optional keys are skipped in minimal mode and
+		// commented out (attached to the next live key) in annotated mode.
+		required := stringSet(m["required"])
+		var pending []string
 		keys := sortedKeys(props)
 		for _, k := range keys {
 			subSchema, _ := props[k].(map[string]any)
-			keyNode := &y3.Node{Kind: y3.ScalarNode, Tag: "!!str", Value: k}
-
-			// Add description as a comment above the key
-			if desc, ok := subSchema["description"].(string); ok && strings.TrimSpace(desc) != "" {
-				keyNode.HeadComment = desc
+			if !required[k] && opts.Mode == "minimal" {
+				continue
+			}
+			if !required[k] && opts.Mode == "annotated" {
+				pending = append(pending, commentedOutKey(k, subSchema, opts))
+				continue
+			}
+			keyNode, valNode := samplePair(k, subSchema, required[k], opts)
+			if len(pending) > 0 {
+				keyNode.HeadComment = joinComments(strings.Join(pending, "\n"), keyNode.HeadComment)
+				pending = nil
 			}
-
-			valNode := sampleNodeWithComments(subSchema)
 			node.Content = append(node.Content, keyNode, valNode)
 		}
+		if len(pending) > 0 {
+			if n := len(node.Content); n > 0 {
+				node.Content[n-2].FootComment = strings.Join(pending, "\n")
+			} else if len(props) > 0 {
+				// only commented-out keys: an empty mapping would be written
+				// as a flow "{ … }", so leave the value empty (see nestComment)
+				return &y3.Node{Kind: y3.ScalarNode, Tag: "!!null", HeadComment: strings.Join(pending, "\n")}
+			}
+		}
 
 		// If no properties but additionalProperties is a schema, synthesize one example field
-		if len(node.Content) == 0 {
+		if len(props) == 0 {
 			if aps, ok := m["additionalProperties"].(map[string]any); ok {
 				keyNode := &y3.Node{Kind: y3.ScalarNode, Tag: "!!str", Value: "key"}
-				if desc, ok := aps["description"].(string); ok && strings.TrimSpace(desc) != "" {
-					keyNode.HeadComment = desc
-				}
-				valNode := sampleNodeWithComments(aps)
+				keyNode.HeadComment = keyComment(opts.resolve(aps), false, opts.CommentStyle)
+				valNode := sampleNodeWithComments(aps, opts)
 				node.Content = append(node.Content, keyNode, valNode)
 			}
 		}
@@ -136,32 +196,27 @@ func sampleNodeWithComments(s any) *y3.Node {
 
 	case "array":
 		seq := &y3.Node{Kind: y3.SequenceNode}
-		if items, ok := m["items"].(map[string]any); ok {
-			_ = items
-			// default behavior: empty array (uncomment next line to emit one placeholder element)
-			// seq.Content = append(seq.Content, sampleNodeWithComments(items))
+		if items, ok := m["items"].(map[string]any); ok && !opts.inRef(items) {
+			for i := 0; i < arrayItemCount(m, opts.ArrayItems); i++ {
+				item := sampleNodeWithComments(items, opts)
+				// describe the element once, above the first one
+				if i == 0 {
+					item.HeadComment = joinComments(keyComment(opts.resolve(items), false, opts.CommentStyle), item.HeadComment)
+					item.FootComment = alternativesComment(items, opts, func(n *y3.Node) *y3.Node {
+						return &y3.Node{Kind: y3.SequenceNode, Content: []*y3.Node{n}}
+					})
+				}
+				seq.Content = append(seq.Content, item)
+			}
+			if n, _ := m["minItems"].(float64); len(seq.Content) == maxSampleItems && n > maxSampleItems {
+				first := seq.Content[0]
+				first.HeadComment = joinComments(first.HeadComment, fmt.Sprintf("minItems is %v; only %d elements shown", n, maxSampleItems))
+			}
 		}
 		return seq
 
 	case "string":
-		// format-aware hints
-		if fmtStr, ok := m["format"].(string); ok {
-			switch fmtStr {
-			case "date-time":
-				return valueToYAMLNode("2025-01-01T00:00:00Z")
-			case "date":
-				return valueToYAMLNode("2025-01-01")
-			case "time":
-				return valueToYAMLNode("00:00:00")
-			case "email":
-				return valueToYAMLNode("user@example.com")
-			case "hostname":
-				return valueToYAMLNode("example.com")
-			case "uri":
-				return valueToYAMLNode("https://example.com")
-			}
-		}
-		return valueToYAMLNode("")
+		return valueToYAMLNode(stringPlaceholder(m))
 
 	case "integer":
 		return valueToYAMLNode(0)
@@ -173,39 +228,19 @@ func sampleNodeWithComments(s any) *y3.Node {
 		return valueToYAMLNode(nil)
 	}
 
-	// If "type" missing but "properties" exist, assume object
-	if props, ok := m["properties"].(map[string]any); ok {
-		node := &y3.Node{Kind: y3.MappingNode}
-		keys := sortedKeys(props)
-		for _, k := range keys {
-			subSchema, _ := props[k].(map[string]any)
-			keyNode := &y3.Node{Kind: y3.ScalarNode, Tag: "!!str", Value: k}
-			if desc, ok := subSchema["description"].(string); ok && strings.TrimSpace(desc) != "" {
-				keyNode.HeadComment = desc
-			}
-			valNode := sampleNodeWithComments(subSchema)
-			node.Content = append(node.Content, keyNode, valNode)
-		}
-		return node
-	}
-
-	// If "type" missing but "items" exist, assume array
-	if items, ok := m["items"].(map[string]any); ok {
-		seq := &y3.Node{Kind: y3.SequenceNode}
-		_ = items
-		// seq.Content = append(seq.Content, sampleNodeWithComments(items))
-		return seq
-	}
-
 	// Fallback
 	return valueToYAMLNode(nil)
 }
 
-func sampleForSchemaPlain(s any) any {
+func sampleForSchemaPlain(s any, opts SampleOptions) any {
 	m, ok := s.(map[string]any)
 	if !ok {
 		return nil
 	}
+	if !opts.enterRef(m) {
+		return nil
+	}
+	m = opts.resolve(m)
 
 	if v, ok := m["default"]; ok {
 		return v
@@ -216,68 +251,44 @@ func sampleForSchemaPlain(s any) any {
 	if enum, ok := m["enum"].([]any); ok && len(enum) > 0 {
 		return enum[0]
 	}
-
-	if allOf, ok := m["allOf"].([]any); ok && len(allOf) > 0 {
-		merged := map[string]any{}
-		for _, sub := range allOf {
-			subSample := sampleForSchemaPlain(sub)
-			if subObj, ok := subSample.(map[string]any); ok {
-				for k, v := range subObj {
-					merged[k] = v
-				}
-			}
-		}
-		if len(merged) > 0 {
-			return merged
-		}
-		return sampleForSchemaPlain(allOf[0])
+	if ex, ok := m["examples"].([]any); ok && len(ex) > 0 {
+		return ex[0]
 	}
-
-	if anyOf, ok := m["anyOf"].([]any); ok && len(anyOf) > 0 {
-		return sampleForSchemaPlain(anyOf[0])
+	if v, ok := m["x-example"]; ok {
+		return v
 	}
-	if oneOf, ok := m["oneOf"].([]any); ok && len(oneOf) > 0 {
-		return sampleForSchemaPlain(oneOf[0])
+
+	if alts := alternatives(m); len(alts) > 0 {
+		return sampleForSchemaPlain(branchSchema(m, alts[0]), opts)
 	}
 
-	switch m["type"] {
+	switch schemaKind(m) {
 	case "object":
 		props, _ := m["properties"].(map[string]any)
 		out := map[string]any{}
+		required := stringSet(m["required"])
 		for name, raw := range props {
-			out[name] = sampleForSchemaPlain(raw)
+			if !required[name] && (opts.Mode == "minimal" || opts.Mode == "annotated") {
+				continue
+			}
+			out[name] = sampleForSchemaPlain(raw, opts)
 		}
-		if len(out) == 0 {
+		if len(props) == 0 {
 			if aps, ok := m["additionalProperties"].(map[string]any); ok {
-				out["key"] = sampleForSchemaPlain(aps)
+				out["key"] = sampleForSchemaPlain(aps, opts)
 			}
 		}
 		return out
 	case "array":
-		if items, ok := m["items"].(map[string]any); ok {
-			_ = items
-			return []any{}
-			// return []any{ sampleForSchemaPlain(items) }
-		}
-		return []any{}
-	case "string":
-		if fmtStr, ok := m["format"].(string); ok {
-			switch fmtStr {
-			case "date-time":
-				return "2025-01-01T00:00:00Z"
-			case "date":
-				return "2025-01-01"
-			case "time":
-				return "00:00:00"
-			case "email":
-				return "user@example.com"
-			case "hostname":
-				return "example.com"
-			case "uri":
-				return "https://example.com"
+		out := []any{}
+		if items, ok := m["items"].(map[string]any); ok && !opts.inRef(items) {
+			for i := 0; i < arrayItemCount(m, opts.ArrayItems); i++ {
+				out = append(out, sampleForSchemaPlain(items, opts))
 			}
 		}
-		return ""
+		return out
+	case "string":
+		return stringPlaceholder(m)
 	case "integer":
 		return 0
 	case "number":
@@ -288,17 +299,6 @@ func sampleForSchemaPlain(s any) any {
 		return nil
 	}
 
-	if props, ok := m["properties"].(map[string]any); ok {
-		out := map[string]any{}
-		for name, raw := range props {
-			out[name] = sampleForSchemaPlain(raw)
-		}
-		return out
-	}
-	if items, ok := m["items"].(map[string]any); ok {
-		_ = items
-		return []any{}
-	}
 	return nil
 }
 
@@ -350,6 +350,214 @@ func valueToYAMLNode(v any) *y3.Node {
 
 // ---------------------- helpers ----------------------
 
+// schemaKind returns the effective type of a schema node: its "type" (the
+// first non-null one, in declared order, when it is a list), else "object" if
+// it has properties and "array" if it has items. Shared by the samplers and
+// code generators.
+func schemaKind(m map[string]any) string {
+	types := declaredTypes(m)
+	for _, t := range types {
+		if t != "null" {
+			return t
+		}
+	}
+	if len(types) > 0 {
+		return "null"
+	}
+	if _, ok := m["properties"].(map[string]any); ok {
+		return "object"
+	}
+	if _, ok := m["items"].(map[string]any); ok {
+		return "array"
+	}
+	return ""
+}
+
+// samplePair builds the key and sample value nodes for property k: the
+// description (and, per --comment-style, an annotation) goes above the key,
+// oneOf/anyOf alternatives below it.
+func samplePair(k string, sub map[string]any, required bool, opts SampleOptions) (*y3.Node, *y3.Node) {
+	keyNode := &y3.Node{Kind: y3.ScalarNode, Tag: "!!str", Value: k}
+	keyNode.HeadComment = keyComment(opts.resolve(sub), required, opts.CommentStyle)
+	valNode := sampleNodeWithComments(sub, opts)
+	if valNode.Kind == y3.ScalarNode && valNode.Value == "" && valNode.HeadComment != "" {
+		valNode.HeadComment = nestComment(valNode.HeadComment)
+	}
+	keyNode.FootComment = alternativesComment(sub, opts, func(n *y3.Node) *y3.Node {
+		return &y3.Node{Kind: y3.MappingNode, Content: []*y3.Node{{Kind: y3.ScalarNode, Tag: "!!str", Value: k}, n}}
+	})
+	return keyNode, valNode
+}
+
+// commentedOutKey renders "key: <sample>" (all nested keys included) as
+// comment lines, preceded by the property's comment.
+func commentedOutKey(key string, sub map[string]any, opts SampleOptions) string {
+	style := opts.CommentStyle
+	opts.Mode = "full"
+	pair := &y3.Node{Kind: y3.MappingNode, Content: []*y3.Node{
+		{Kind: y3.ScalarNode, Tag: "!!str", Value: key},
+		sampleNodeWithComments(sub, opts),
+	}}
+	return joinComments(keyComment(opts.resolve(sub), false, style), commentOut(pair))
+}
+
+// nestComment indents the commented-out keys of an object left empty in
+// annotated mode one level inside the comment, since yaml.v3 writes them at
+// the column of the object's own key: removing "# " then nests them under it.
+func nestComment(c string) string {
+	lines := strings.Split(c, "\n")
+	for i, l := range lines {
+		l = strings.TrimPrefix(strings.TrimPrefix(l, "#"), " ")
+		lines[i] = strings.TrimRight("#   "+l, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// commentOut encodes n as YAML and turns every line into a comment.
+func commentOut(n *y3.Node) string {
+	var buf bytes.Buffer
+	enc := y3.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(n); err != nil {
+		return ""
+	}
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight("# "+l, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// alternatives returns the oneOf (else anyOf) branches of m.
+func alternatives(m map[string]any) []any {
+	if oneOf, ok := m["oneOf"].([]any); ok && len(oneOf) > 0 {
+		return oneOf
+	}
+	anyOf, _ := m["anyOf"].([]any)
+	return anyOf
+}
+
+// branchSchema combines m's own keywords with one of its oneOf/anyOf branches.
+func branchSchema(m map[string]any, branch any) map[string]any {
+	return map[string]any{"allOf": []any{without(without(m, "oneOf"), "anyOf"), branch}}
+}
+
+// alternativesComment renders the oneOf/anyOf branches of s that the sample
+// did not pick as commented-out YAML, each labeled with the branch title
+// ("Alternative: GCS"); wrap places a branch's value in context (e.g. under
+// its key). It is empty when s has a single branch or a fixed value.
+func alternativesComment(s map[string]any, opts SampleOptions, wrap func(*y3.Node) *y3.Node) string {
+	m := opts.resolve(s)
+	alts := alternatives(m)
+	if len(alts) < 2 || opts.CommentStyle == "none" {
+		return ""
+	}
+	for _, k := range []string{"default", "const", "enum", "examples", "x-example"} {
+		if _, ok := m[k]; ok {
+			return ""
+		}
+	}
+	opts.Mode = "full"
+	var blocks []string
+	for i, b := range alts[1:] {
+		label := fmt.Sprintf("option %d", i+2)
+		if bm, ok := b.(map[string]any); ok {
+			label = stringOr(opts.resolve(bm)["title"], label)
+		}
+		blocks = append(blocks, joinComments("Alternative: "+label, commentOut(wrap(sampleNodeWithComments(branchSchema(m, b), opts)))))
+	}
+	return strings.Join(blocks, "\n")
+}
+
+// keyComment is the comment above a key. Style "description" (the default)
+// uses the description; "full" adds the title as a header and a line with the
+// type, a "(required)" marker, enum values, bounds, pattern, format and
+// default; "none" emits no comments.
+func keyComment(sub map[string]any, required bool, style string) string {
+	desc := strings.TrimSpace(stringOr(sub["description"], ""))
+	switch style {
+	case "none":
+		return ""
+	case "full":
+		return joinComments(strings.TrimSpace(stringOr(sub["title"], "")), desc, schemaAnnotation(sub, required))
+	}
+	return desc
+}
+
+// schemaAnnotation summarizes a property's constraints on one line, e.g.
+// "type: integer (required); minimum: 1; maximum: 65535; default: 8080".
+func schemaAnnotation(sub map[string]any, required bool) string {
+	var parts []string
+	t, _ := sub["type"].(string)
+	if list := stringList(sub["type"]); len(list) > 0 {
+		t = strings.Join(list, "|")
+	} else if t == "" {
+		t = schemaKind(sub)
+	}
+	switch {
+	case t != "" && required:
+		parts = append(parts, "type: "+t+" (required)")
+	case t != "":
+		parts = append(parts, "type: "+t)
+	case required:
+		parts = append(parts, "(required)")
+	}
+	if enum, ok := sub["enum"].([]any); ok && len(enum) > 0 {
+		vals := make([]string, len(enum))
+		for i, v := range enum {
+			vals[i] = formatValue(v)
+		}
+		parts = append(parts, "enum: "+strings.Join(vals, ", "))
+	}
+	for _, k := range annotatedKeywords {
+		if v, ok := sub[k]; ok {
+			if s, ok := v.(string); ok {
+				parts = append(parts, k+": "+s)
+			} else {
+				parts = append(parts, k+": "+formatValue(v))
+			}
+		}
+	}
+	if v, ok := sub["default"]; ok {
+		parts = append(parts, "default: "+formatValue(v))
+	}
+	return strings.Join(parts, "; ")
+}
+
+var annotatedKeywords = []string{
+	"minimum", "exclusiveMinimum", "maximum", "exclusiveMaximum",
+	"minLength", "maxLength", "minItems", "maxItems", "pattern", "format",
+}
+
+// joinComments joins comment blocks, skipping empty ones.
+func joinComments(parts ...string) string {
+	var out []string
+	for _, p := range parts {
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// maxSampleItems caps the example elements --array-items=minItems emits per
+// array; a larger minItems gets a sample that is shorter than required (and
+// says so in a comment) rather than a huge one.
+const maxSampleItems = 100
+
+// arrayItemCount is how many example elements to emit for array schema m.
+func arrayItemCount(m map[string]any, mode string) int {
+	switch mode {
+	case "1":
+		return 1
+	case "minItems":
+		if n, ok := m["minItems"].(float64); ok && n > 0 {
+			return min(int(n), maxSampleItems)
+		}
+	}
+	return 0
+}
+
 func sortedKeys(m map[string]any) []string {
 	keys := make([]string, 0, len(m))
 	for k := range m {
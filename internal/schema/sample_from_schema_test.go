@@ -0,0 +1,394 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/yaml"
+)
+
+// buildSample runs BuildSampleFromSchema on a schema written to a temp file.
+func buildSample(t *testing.T, schema string, opts SampleOptions) (string, error) {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), "config.schema.yaml")
+	if err := os.WriteFile(p, []byte(schema), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out, err := BuildSampleFromSchema(p, opts)
+	return string(out), err
+}
+
+func TestSampleArrayItemsAndExamples(t *testing.T) {
+	const schema = `type: object
+properties:
+  hosts:
+    type: array
+    minItems: 2
+    items: {type: string, description: A host name, examples: [api.example.com]}
+  ports:
+    type: array
+    items:
+      type: object
+      properties:
+        port: {type: integer, default: 80}
+  tag: {type: string, examples: [v1.2.3, v2]}
+`
+	tests := []struct {
+		name       string
+		schema     string
+		arrayItems string
+		want       string
+		wantErr    string
+	}{
+		{
+			name:   "empty arrays by default, first example used",
+			schema: schema,
+			want:   "hosts: []\nports: []\ntag: v1.2.3\n",
+		},
+		{
+			name:       "one element",
+			schema:     schema,
+			arrayItems: "1",
+			want:       "hosts:\n  # A host name\n  - api.example.com\nports:\n  - port: 80\ntag: v1.2.3\n",
+		},
+		{
+			name:       "minItems elements",
+			schema:     schema,
+			arrayItems: "minItems",
+			want:       "hosts:\n  # A host name\n  - api.example.com\n  - api.example.com\nports: []\ntag: v1.2.3\n",
+		},
+		{
+			name:       "minItems above the cap",
+			schema:     "type: object\nproperties:\n  big: {type: array, minItems: 1000, items: {type: integer}}\n",
+			arrayItems: "minItems",
+			want:       "big:\n  # minItems is 1000; only 100 elements shown\n" + strings.Repeat("  - 0\n", 100),
+		},
+		{
+			name:       "unused definitions are not sampled",
+			schema:     "type: object\ndefinitions:\n  unused: {type: array, minItems: 500}\nproperties:\n  big: {type: array, minItems: 1, items: {type: integer}}\n",
+			arrayItems: "minItems",
+			want:       "big:\n  - 0\n",
+		},
+		{
+			name:       "minItems at the cap",
+			schema:     "type: object\nproperties:\n  big: {type: array, minItems: 100, items: {type: integer}}\n",
+			arrayItems: "minItems",
+			want:       "big:\n" + strings.Repeat("  - 0\n", 100),
+		},
+		{
+			name:       "invalid mode",
+			schema:     schema,
+			arrayItems: "2",
+			wantErr:    `invalid --array-items "2"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildSample(t, tt.schema, SampleOptions{ArrayItems: tt.arrayItems})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.TrimSpace(got) != strings.TrimSpace(tt.want) {
+				t.Errorf("sample:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSampleModes(t *testing.T) {
+	const schema = `type: object
+required: [name, image]
+properties:
+  name: {type: string, description: App name, default: api}
+  replicas: {type: integer, default: 2, description: Replica count}
+  image:
+    type: object
+    required: [repository]
+    properties:
+      repository: {type: string, default: nginx}
+      tag: {type: string, default: latest}
+`
+	tests := []struct {
+		mode    string
+		want    string
+		wantErr string
+	}{
+		{
+			mode: "",
+			want: "image:\n  repository: nginx\n  tag: latest\n# App name\nname: api\n# Replica count\nreplicas: 2\n",
+		},
+		{
+			mode: "full",
+			want: "image:\n  repository: nginx\n  tag: latest\n# App name\nname: api\n# Replica count\nreplicas: 2\n",
+		},
+		{
+			mode: "minimal",
+			want: "image:\n  repository: nginx\n# App name\nname: api\n",
+		},
+		{
+			mode: "annotated",
+			want: "image:\n  repository: nginx\n  # tag: latest\n# App name\nname: api\n# Replica count\n# replicas: 2\n",
+		},
+		{
+			mode:    "short",
+			wantErr: `invalid --mode "short"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			got, err := buildSample(t, schema, SampleOptions{Mode: tt.mode})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.TrimSpace(got) != strings.TrimSpace(tt.want) {
+				t.Errorf("sample:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+// An object whose keys are all optional is written as an empty block key in
+// annotated mode, and uncommenting its keys gives valid YAML.
+func TestSampleAnnotatedEmptyObject(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		want   string
+	}{
+		{
+			name:   "nested",
+			schema: "type: object\nrequired: [app]\nproperties:\n  app:\n    type: object\n    required: [db]\n    properties:\n      db:\n        type: object\n        properties:\n          host: {type: string, default: localhost}\n          opts: {type: object, properties: {ssl: {type: boolean}}}\n",
+			want:   "app:\n  db:\n  #   host: localhost\n  #   opts:\n  #     ssl: false\n",
+		},
+		{
+			name:   "root",
+			schema: "type: object\nproperties:\n  host: {type: string, default: localhost}\n",
+			want:   "# host: localhost\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildSample(t, tt.schema, SampleOptions{Mode: "annotated"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.TrimSpace(got) != strings.TrimSpace(tt.want) {
+				t.Errorf("sample:\n%s\nwant:\n%s", got, tt.want)
+			}
+			uncommented := strings.ReplaceAll(got, "# ", "")
+			var full any
+			if err := yaml.Unmarshal([]byte(uncommented), &full); err != nil {
+				t.Errorf("uncommented sample is invalid YAML: %v\n%s", err, uncommented)
+			}
+			if want, _ := buildSample(t, tt.schema, SampleOptions{}); !reflect.DeepEqual(full, yamlSchema(t, want)) {
+				t.Errorf("uncommented sample:\n%s\nwant the full sample:\n%s", uncommented, want)
+			}
+		})
+	}
+}
+
+func TestSampleCommentStyles(t *testing.T) {
+	const schema = `title: App
+description: App settings
+type: object
+required: [name]
+properties:
+  name: {type: string, description: App name, minLength: 1, default: api}
+  replicas: {type: integer, default: 2, minimum: 1, maximum: 10, description: Replica count}
+  env: {type: string, enum: [dev, prod], default: dev}
+  host: {type: string, format: hostname, title: Host, default: example.com}
+`
+	tests := []struct {
+		style   string
+		want    string
+		wantErr string
+	}{
+		{
+			style: "",
+			want:  "env: dev\nhost: example.com\n# App name\nname: api\n# Replica count\nreplicas: 2\n",
+		},
+		{
+			style: "none",
+			want:  "env: dev\nhost: example.com\nname: api\nreplicas: 2\n",
+		},
+		{
+			style: "full",
+			want: `# App
+# App settings
+
+# type: string; enum: "dev", "prod"; default: "dev"
+env: dev
+# Host
+# type: string; format: hostname; default: "example.com"
+host: example.com
+# App name
+# type: string (required); minLength: 1; default: "api"
+name: api
+# Replica count
+# type: integer; minimum: 1; maximum: 10; default: 2
+replicas: 2
+`,
+		},
+		{
+			style:   "verbose",
+			wantErr: `invalid --comment-style "verbose"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.style, func(t *testing.T) {
+			got, err := buildSample(t, schema, SampleOptions{CommentStyle: tt.style})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.TrimSpace(got) != strings.TrimSpace(tt.want) {
+				t.Errorf("sample:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSampleAllOf(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		opts   SampleOptions
+		want   string
+	}{
+		{
+			name: "branches merged with their comments",
+			schema: `type: object
+definitions:
+  meta:
+    type: object
+    properties:
+      name: {type: string, description: Resource name, default: api}
+properties:
+  app:
+    description: The app
+    allOf:
+      - $ref: "#/definitions/meta"
+      - properties:
+          version: {type: string, description: Version, default: "1.0"}
+`,
+			want: "# The app\napp:\n  # Resource name\n  name: api\n  # Version\n  version: \"1.0\"\n",
+		},
+		{
+			name: "required from any branch counts for minimal",
+			schema: `type: object
+allOf:
+  - properties:
+      a: {type: string, default: x}
+      b: {type: string, default: bee}
+  - required: [b]
+`,
+			opts: SampleOptions{Mode: "minimal"},
+			want: "b: bee\n",
+		},
+		{
+			name: "property declared in two branches",
+			schema: `type: object
+allOf:
+  - properties:
+      port: {type: integer, description: Service port}
+  - properties:
+      port: {default: 8080}
+`,
+			want: "# Service port\nport: 8080\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildSample(t, tt.schema, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.TrimSpace(got) != strings.TrimSpace(tt.want) {
+				t.Errorf("sample:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSampleAlternatives(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		want   string
+	}{
+		{
+			name: "other branches commented out with their title",
+			schema: `type: object
+properties:
+  storage:
+    description: Where to store
+    oneOf:
+      - title: S3
+        type: object
+        properties:
+          bucket: {type: string, default: my-bucket}
+      - title: GCS
+        type: object
+        properties:
+          gcsBucket: {type: string, default: gcs-bucket}
+`,
+			want: "# Where to store\nstorage:\n  bucket: my-bucket\n# Alternative: GCS\n# storage:\n#   gcsBucket: gcs-bucket\n",
+		},
+		{
+			name: "scalar anyOf",
+			schema: `type: object
+properties:
+  port:
+    anyOf:
+      - {type: integer, default: 80}
+      - {type: string, title: Named port, default: http}
+`,
+			want: "port: 80\n# Alternative: Named port\n# port: http\n",
+		},
+		{
+			name: "root alternatives",
+			schema: `oneOf:
+  - title: Small
+    type: object
+    properties:
+      size: {type: string, default: s}
+  - title: Large
+    type: object
+    properties:
+      size: {type: string, default: l}
+`,
+			want: "size: s\n\n# Alternative: Large\n# size: l\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildSample(t, tt.schema, SampleOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.TrimSpace(got) != strings.TrimSpace(tt.want) {
+				t.Errorf("sample:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
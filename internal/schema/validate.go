@@ -11,10 +11,15 @@ import (
 )
 
 // ValidateYAMLWithSchema validates YAML config against a JSON Schema (YAML or JSON) using gojsonschema.
+// The schema's own defaults, consts, enums and examples are checked first.
 func ValidateYAMLWithSchema(schemaPath, dataPath string) error {
-	sraw, err := os.ReadFile(schemaPath)
+	schemaJSON, err := readSchemaJSON(schemaPath)
 	if err != nil {
-		return fmt.Errorf("read schema: %w", err)
+		return err
+	}
+	_, err = parseSchema(schemaJSON)
+	if err != nil {
+		return err
 	}
 	draw, err := os.ReadFile(dataPath)
 	if err != nil {
@@ -22,13 +27,6 @@ func ValidateYAMLWithSchema(schemaPath, dataPath string) error {
 	}
 
 	// Convert YAML to JSON where needed
-	schemaJSON := sraw
-	if looksLikeYAML(schemaPath, sraw) {
-		schemaJSON, err = yaml.YAMLToJSON(sraw)
-		if err != nil {
-			return fmt.Errorf("schema YAML->JSON: %w", err)
-		}
-	}
 	dataJSON := draw
 	if looksLikeYAML(dataPath, draw) {
 		dataJSON, err = yaml.YAMLToJSON(draw)
@@ -36,7 +34,12 @@ func ValidateYAMLWithSchema(schemaPath, dataPath string) error {
 			return fmt.Errorf("data YAML->JSON: %w", err)
 		}
 	}
+	return validateJSON(schemaJSON, dataJSON)
+}
 
+// validateJSON validates a JSON document against a JSON schema, collecting all
+// errors into a single message.
+func validateJSON(schemaJSON, dataJSON []byte) error {
 	sl := gojsonschema.NewBytesLoader(schemaJSON)
 	dl := gojsonschema.NewBytesLoader(dataJSON)
 	res, err := gojsonschema.Validate(sl, dl)
@@ -53,3 +56,17 @@ func ValidateYAMLWithSchema(schemaPath, dataPath string) error {
 	}
 	return errors.New("schema validation failed:\n" + b.String())
 }
+
+// ValidateValues validates rendered/patched values (YAML) against a chart's
+// values schema (YAML or JSON), e.g. values.schema.json.
+func ValidateValues(schemaPath string, values []byte) error {
+	schemaJSON, err := readSchemaJSON(schemaPath)
+	if err != nil {
+		return err
+	}
+	dataJSON, err := yaml.YAMLToJSON(values)
+	if err != nil {
+		return fmt.Errorf("values YAML->JSON: %w", err)
+	}
+	return validateJSON(schemaJSON, dataJSON)
+}
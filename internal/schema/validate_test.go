@@ -0,0 +1,67 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateValues(t *testing.T) {
+	const valuesSchema = `{
+  "type": "object",
+  "required": ["replicaCount"],
+  "properties": {
+    "replicaCount": {"type": "integer", "minimum": 1},
+    "image": {"type": "object", "properties": {"tag": {"type": "string"}}}
+  }
+}`
+	tests := []struct {
+		name    string
+		values  string
+		wantErr []string
+	}{
+		{
+			name:   "valid",
+			values: "replicaCount: 2\nimage:\n  tag: \"1.0\"\nextra: true\n",
+		},
+		{
+			name:    "missing required",
+			values:  "image:\n  tag: v1\n",
+			wantErr: []string{"schema validation failed", "replicaCount is required"},
+		},
+		{
+			name:    "every error is listed",
+			values:  "replicaCount: 0\nimage:\n  tag: 1\n",
+			wantErr: []string{"- replicaCount: Must be greater than or equal to 1", "- image.tag: Invalid type. Expected: string, given: integer"},
+		},
+		{
+			name:    "invalid YAML",
+			values:  "replicaCount: [\n",
+			wantErr: []string{"values YAML->JSON"},
+		},
+	}
+	p := filepath.Join(t.TempDir(), "values.schema.json")
+	if err := os.WriteFile(p, []byte(valuesSchema), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateValues(p, []byte(tt.values))
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("err = nil, want %q", tt.wantErr)
+			}
+			for _, w := range tt.wantErr {
+				if !strings.Contains(err.Error(), w) {
+					t.Errorf("err = %v, want it to contain %q", err, w)
+				}
+			}
+		})
+	}
+}
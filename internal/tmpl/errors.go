@@ -0,0 +1,301 @@
+package tmpl
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// Error is a template parse or execution error located in the template file.
+type Error struct {
+	File    string
+	Line    int // 1-based; 0 if unknown
+	Column  int // 1-based; 0 if unknown
+	Message string
+	Excerpt string // the source line with a caret under Column
+}
+
+func (e *Error) Error() string {
+	loc := e.File
+	if e.Line > 0 {
+		loc += ":" + strconv.Itoa(e.Line)
+		if e.Column > 0 {
+			loc += ":" + strconv.Itoa(e.Column)
+		}
+	}
+	s := loc + ": " + e.Message
+	if e.Excerpt != "" {
+		s += "\n" + e.Excerpt
+	}
+	return s
+}
+
+// text/template reports "template: NAME:LINE: msg" for parse errors and
+// "template: NAME:LINE:COL: executing "NAME" at <node>: msg" (0-based
+// column) for execution errors.
+var (
+	locRe     = regexp.MustCompile(`^(\d+)(?::(\d+))?: (.*)$`)
+	execRe    = regexp.MustCompile(`^executing "[^"]*" at <(.*?)>: (.*)$`)
+	noEntryRe = regexp.MustCompile(`^map has no entry for key "(.*)"$`)
+	quotedRe  = regexp.MustCompile(`"([^"]+)"`)
+	// an action spanning lines that is still open at the end of the file
+	unclosedRe = regexp.MustCompile(`^unclosed action started at .*:(\d+)$`)
+)
+
+// newError locates a text/template error in src (the template text of
+// file, parsed under the name file) and, for missingkey=error failures,
+// names the full missing key path and the nearest existing keys in data.
+func newError(file, src string, err error, data any) error {
+	msg, ok := strings.CutPrefix(err.Error(), "template: "+file+":")
+	if !ok {
+		return err
+	}
+	m := locRe.FindStringSubmatch(msg)
+	if m == nil {
+		return err
+	}
+	e := &Error{File: file, Message: m[3]}
+	e.Line, _ = strconv.Atoi(m[1])
+	lines := strings.Split(src, "\n")
+	line := ""
+	if e.Line >= 1 && e.Line <= len(lines) {
+		line = lines[e.Line-1]
+	}
+	if u := unclosedRe.FindStringSubmatch(e.Message); u != nil {
+		// point at the last "{{" of the line the action starts on
+		e.Line, _ = strconv.Atoi(u[1])
+		e.Message = "unclosed action"
+		line = ""
+		if e.Line >= 1 && e.Line <= len(lines) {
+			line = lines[e.Line-1]
+		}
+		e.Column = strings.LastIndex(line, "{{") + 1
+	} else if m[2] == "" && strings.HasSuffix(e.Message, "unexpected EOF") {
+		// an unclosed action or block: point past the last non-blank line
+		for e.Line > 1 && strings.TrimSpace(line) == "" {
+			e.Line--
+			line = lines[e.Line-1]
+		}
+		e.Column = len(line) + 1
+	}
+
+	var execErr template.ExecError
+	if m[2] != "" {
+		col, _ := strconv.Atoi(m[2])
+		e.Column = col + 1
+	} else if e.Column == 0 {
+		e.Column = parseErrorColumn(line, e.Message)
+	}
+	if errors.As(err, &execErr) {
+		if x := execRe.FindStringSubmatch(e.Message); x != nil {
+			node, reason := x[1], x[2]
+			e.Message = fmt.Sprintf("at <%s>: %s", node, reason)
+			if k := noEntryRe.FindStringSubmatch(reason); k != nil {
+				e.Message = missingKeyMessage(node, k[1], data)
+			}
+		}
+	}
+	e.Excerpt = excerpt(e.Line, line, e.Column)
+	return e
+}
+
+// parseErrorColumn guesses the column of a parse error, which text/template
+// reports by line only: the first quoted token of the message ("}",
+// "nosuch") inside an action, else the line's first action.
+func parseErrorColumn(line, msg string) int {
+	start := strings.Index(line, "{{")
+	if q := quotedRe.FindStringSubmatch(msg); q != nil {
+		if i := strings.Index(line[max(start, 0):], q[1]); i >= 0 {
+			return max(start, 0) + i + 1
+		}
+	}
+	if start >= 0 {
+		return start + 1
+	}
+	return 0
+}
+
+func excerpt(n int, line string, col int) string {
+	if n == 0 {
+		return ""
+	}
+	gutter := fmt.Sprintf("%4d | ", n)
+	s := gutter + line
+	if col > 0 && col <= len(line)+1 {
+		// keep tabs so the caret lines up
+		pad := strings.Map(func(r rune) rune {
+			if r == '\t' {
+				return r
+			}
+			return ' '
+		}, line[:col-1])
+		s += "\n" + strings.Repeat(" ", len(gutter)-2) + "| " + pad + "^"
+	}
+	return s
+}
+
+// missingKeyMessage describes a missingkey=error failure at node (e.g.
+// ".app.imge.tag"): the full path of the missing key and the closest keys
+// that do exist beside it. Fields relative to a range/with dot cannot be
+// placed exactly; they are matched against the maps nested in data when one
+// has a key a single edit away.
+func missingKeyMessage(node, key string, data any) string {
+	fields := fieldChain(node)
+	i := indexOf(fields, key)
+	if i < 0 {
+		return fmt.Sprintf("at <%s>: missing key %q", node, key)
+	}
+	msg := func(path string, near []string) string {
+		s := fmt.Sprintf("at <%s>: missing key %q", node, joinPath(path, key))
+		if len(near) > 0 {
+			for j := range near {
+				near[j] = joinPath(path, near[j])
+			}
+			s += fmt.Sprintf(" (nearest existing: %s)", strings.Join(near, ", "))
+		}
+		return s
+	}
+
+	rootPath, root, rootOK := follow(data, "", fields[:i])
+	if rootOK {
+		if near := nearestKeys(key, root, max(2, len(key)/2)); len(near) > 0 {
+			return msg(rootPath, near)
+		}
+	}
+	var nested []parentMap
+	findChain(data, "", fields[:i], &nested)
+	for _, p := range nested {
+		if near := nearestKeys(key, p.m, 1); len(near) > 0 {
+			return msg(p.path, near)
+		}
+	}
+	if rootOK {
+		return msg(rootPath, nil)
+	}
+	return fmt.Sprintf("at <%s>: missing key %q", node, strings.Join(fields[:i+1], "."))
+}
+
+// fieldChain returns the field names of a ".a.b.c" node, or nil for other
+// nodes (pipelines, variables, function calls).
+func fieldChain(node string) []string {
+	if !strings.HasPrefix(node, ".") || strings.ContainsAny(node, " ($|") {
+		return nil
+	}
+	return strings.Split(node[1:], ".")
+}
+
+func indexOf(ss []string, s string) int {
+	for i, x := range ss {
+		if x == s {
+			return i
+		}
+	}
+	return -1
+}
+
+type parentMap struct {
+	path string
+	m    map[string]any
+}
+
+// findChain collects the maps reached by following chain from v and from
+// every map nested in v (v itself first, then depth first by sorted keys).
+func findChain(v any, path string, chain []string, out *[]parentMap) {
+	if p, m, ok := follow(v, path, chain); ok {
+		*out = append(*out, parentMap{p, m})
+	}
+	switch t := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			findChain(t[k], joinPath(path, k), chain, out)
+		}
+	case []any:
+		for i, e := range t {
+			findChain(e, fmt.Sprintf("%s[%d]", path, i), chain, out)
+		}
+	}
+}
+
+func follow(v any, path string, chain []string) (string, map[string]any, bool) {
+	for _, f := range chain {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return "", nil, false
+		}
+		if v, ok = m[f]; !ok {
+			return "", nil, false
+		}
+		path = joinPath(path, f)
+	}
+	m, ok := v.(map[string]any)
+	return path, m, ok
+}
+
+func joinPath(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+// nearestKeys returns up to three keys of m within limit edits of key
+// (case-insensitive), closest first.
+func nearestKeys(key string, m map[string]any, limit int) []string {
+	type cand struct {
+		k string
+		d int
+	}
+	var cands []cand
+	for k := range m {
+		if d := editDistance(strings.ToLower(key), strings.ToLower(k)); d <= limit {
+			cands = append(cands, cand{k, d})
+		}
+	}
+	sort.Slice(cands, func(i, j int) bool {
+		if cands[i].d != cands[j].d {
+			return cands[i].d < cands[j].d
+		}
+		return cands[i].k < cands[j].k
+	})
+	var out []string
+	for i := 0; i < len(cands) && i < 3; i++ {
+		out = append(out, cands[i].k)
+	}
+	return out
+}
+
+// editDistance is the Levenshtein distance, counting a swap of adjacent
+// characters ("tga" for "tag") as one edit.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
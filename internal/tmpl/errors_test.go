@@ -0,0 +1,77 @@
+package tmpl
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRenderErrors(t *testing.T) {
+	data := map[string]any{
+		"app":   map[string]any{"image": map[string]any{"tag": "v1"}, "title": "api"},
+		"hosts": []any{map[string]any{"name": "a", "port": 80}},
+		"n":     1,
+	}
+	tests := []struct {
+		name string
+		tpl  string
+		want Error
+	}{
+		{
+			name: "missing key with a near miss",
+			tpl:  "app:\n  tag: {{ .app.imge.tag }}\n",
+			want: Error{Line: 2, Column: 15, Message: `at <.app.imge.tag>: missing key "app.imge" (nearest existing: app.image)`,
+				Excerpt: "   2 |   tag: {{ .app.imge.tag }}\n     |               ^"},
+		},
+		{
+			name: "missing key inside range",
+			tpl:  "{{ range .hosts }}\n- {{ .nmae }}\n{{ end }}\n",
+			want: Error{Line: 2, Column: 6, Message: `at <.nmae>: missing key "hosts[0].nmae" (nearest existing: hosts[0].name)`,
+				Excerpt: "   2 | - {{ .nmae }}\n     |      ^"},
+		},
+		{
+			name: "unknown function",
+			tpl:  "a: 1\nb: {{ nosuch .n }}\n",
+			want: Error{Line: 2, Column: 7, Message: `function "nosuch" not defined`,
+				Excerpt: "   2 | b: {{ nosuch .n }}\n     |       ^"},
+		},
+		{
+			name: "unexpected EOF in a block",
+			tpl:  "{{ if .n }}\nx: 1\n\n",
+			want: Error{Line: 2, Column: 5, Message: "unexpected EOF",
+				Excerpt: "   2 | x: 1\n     |     ^"},
+		},
+		{
+			name: "action left open at EOF",
+			tpl:  "a: 1\nb: {{ .n\n  | printf \"%d\"\n",
+			want: Error{Line: 2, Column: 4, Message: "unclosed action",
+				Excerpt: "   2 | b: {{ .n\n     |    ^"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := filepath.Join(t.TempDir(), "template.tmpl")
+			if err := os.WriteFile(p, []byte(tt.tpl), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := RenderWithData(p, data)
+			var got *Error
+			if !errors.As(err, &got) {
+				t.Fatalf("err = %v, want *Error", err)
+			}
+			tt.want.File = p
+			if *got != tt.want {
+				t.Errorf("error:\n%#v\nwant:\n%#v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorString(t *testing.T) {
+	e := &Error{File: "./template.tmpl", Line: 3, Column: 9, Message: "boom", Excerpt: "   3 | x: {{ . }}\n     |         ^"}
+	want := "./template.tmpl:3:9: boom\n   3 | x: {{ . }}\n     |         ^"
+	if got := e.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
@@ -22,13 +22,15 @@ func RenderFromFiles(tplPath, cfgPath string) ([]byte, error) {
 	return RenderWithData(tplPath, data)
 }
 
-// RenderWithData executes the template with a provided data map.
+// RenderWithData executes the template with a provided data map. Parse and
+// execution errors are returned as *Error, located in the template file.
 func RenderWithData(tplPath string, data map[string]any) ([]byte, error) {
 	tplBytes, err := os.ReadFile(tplPath)
 	if err != nil {
 		return nil, err
 	}
-	tpl := template.Must(template.New("values").Funcs(template.FuncMap{
+	// named by its path as given, so errors point at that file
+	tpl, err := template.New(tplPath).Funcs(template.FuncMap{
 		"csv": func(ss any) string {
 			switch v := ss.(type) {
 			case []string:
@@ -75,11 +77,14 @@ func RenderWithData(tplPath string, data map[string]any) ([]byte, error) {
 			b.WriteByte(']')
 			return b.String()
 		},
-	}).Option("missingkey=error").Parse(string(tplBytes)))
+	}).Option("missingkey=error").Parse(string(tplBytes))
+	if err != nil {
+		return nil, newError(tplPath, string(tplBytes), err, data)
+	}
 
 	var buf bytes.Buffer
 	if err := tpl.Execute(&buf, data); err != nil {
-		return nil, err
+		return nil, newError(tplPath, string(tplBytes), err, data)
 	}
 	return buf.Bytes(), nil
 }
@@ -0,0 +1,59 @@
+package tmpl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRenderWithData(t *testing.T) {
+	data := map[string]any{
+		"name":  "api",
+		"hosts": []any{"a", "b"},
+		"tags":  []string{`x"y`, "z"},
+		"port":  80,
+	}
+	tests := []struct {
+		name string
+		tpl  string
+		want string
+	}{
+		{name: "fields", tpl: "name: {{ .name }}\nport: {{ .port }}\n", want: "name: api\nport: 80\n"},
+		{name: "csv", tpl: "{{ csv .hosts }}|{{ csv .tags }}|{{ csv .port }}", want: `a,b|x"y,z|`},
+		{name: "jsonarr", tpl: "{{ jsonarr .hosts }} {{ jsonarr .tags }} {{ jsonarr .port }}", want: `["a","b"] ["x\"y","z"] []`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := filepath.Join(t.TempDir(), "template.tmpl")
+			if err := os.WriteFile(p, []byte(tt.tpl), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := RenderWithData(p, data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("rendered:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderFromFiles(t *testing.T) {
+	dir := t.TempDir()
+	tplPath := filepath.Join(dir, "template.tmpl")
+	cfgPath := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(tplPath, []byte("image: {{ .image.repo }}:{{ .image.tag }}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cfgPath, []byte("image:\n  repo: nginx\n  tag: \"1.27\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := RenderFromFiles(tplPath, cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "image: nginx:1.27\n"; string(got) != want {
+		t.Errorf("rendered %q, want %q", got, want)
+	}
+}